@@ -0,0 +1,142 @@
+// Package analytics summarizes stored attendance history.
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// AttendeeStats is one attendee's attendance history up to a point in time.
+type AttendeeStats struct {
+	Attendee *models.Attendee `json:"-"`
+
+	UserID        string `json:"user_id"`
+	PreferredName string `json:"preferred_name"`
+	LegalName     string `json:"legal_name"`
+	IsHost        bool   `json:"is_host"`
+
+	// EventsRSVPed counts yes RSVPs, including ones for upcoming events.
+	EventsRSVPed   int `json:"events_rsvped"`
+	EventsDeclined int `json:"events_declined"`
+	EventsUpcoming int `json:"events_upcoming"`
+	// EventsAttended counts past events attended, walk-ins included.
+	EventsAttended int `json:"events_attended"`
+	WalkIns        int `json:"walk_ins"`
+	NoShows        int `json:"no_shows"`
+	// NoShowRate is NoShows over the yes RSVPs for past events, or zero
+	// when there are none.
+	NoShowRate float64 `json:"no_show_rate"`
+
+	// CurrentStreak and LongestStreak count consecutive past events
+	// attended out of those RSVPed yes to or walked in to.
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+
+	FirstSeen       *time.Time `json:"first_seen,omitempty"`
+	LastSeen        *time.Time `json:"last_seen,omitempty"`
+	DaysSinceJoined *int       `json:"days_since_joined,omitempty"`
+}
+
+// CheckInsTaken returns the IDs of the events among attendances that
+// anyone's check-in status was recorded for.
+func CheckInsTaken(attendances []*models.Attendance) map[string]bool {
+	taken := map[string]bool{}
+	for _, attendance := range attendances {
+		if attendance.CheckInStatus() != models.NotCheckedIn {
+			taken[attendance.Event().ID()] = true
+		}
+	}
+	return taken
+}
+
+// Attended reports whether an attendance counts as showing up. A recorded
+// check-in decides it, and at events where check-ins were taken, as listed
+// by CheckInsTaken, nobody else came. For events nobody checked in at, a
+// yes RSVP is the best signal available.
+func Attended(a *models.Attendance, checkInsTaken map[string]bool) bool {
+	if a.CheckInStatus() != models.NotCheckedIn {
+		return a.CameInPerson()
+	}
+	return a.RSVP() && !checkInsTaken[a.Event().ID()]
+}
+
+// byEventTime orders attendances chronologically, with events that have
+// no time last.
+type byEventTime []*models.Attendance
+
+func (b byEventTime) Len() int      { return len(b) }
+func (b byEventTime) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byEventTime) Less(i, j int) bool {
+	ti, tj := b[i].Event().Time(), b[j].Event().Time()
+	if ti == nil || tj == nil {
+		return ti != nil && tj == nil
+	}
+	return ti.Before(*tj)
+}
+
+// ComputeAttendeeStats summarizes the attendee's attendances as of now.
+// Attendances belonging to other attendees are ignored. checkInsTaken lists
+// the events check-ins were taken at, as returned by CheckInsTaken for
+// everyone's attendances.
+func ComputeAttendeeStats(attendee *models.Attendee, attendances []*models.Attendance, checkInsTaken map[string]bool, now time.Time) *AttendeeStats {
+	stats := &AttendeeStats{
+		Attendee:      attendee,
+		UserID:        attendee.UserID(),
+		PreferredName: attendee.PreferredName(),
+		LegalName:     attendee.LegalName(),
+		IsHost:        attendee.IsHost(),
+	}
+	if joined := attendee.JoinedDate(); joined != nil && !joined.After(now) {
+		days := int(now.Sub(*joined).Hours() / 24)
+		stats.DaysSinceJoined = &days
+	}
+
+	var own []*models.Attendance
+	for _, attendance := range attendances {
+		if attendance.Attendee().UserID() == attendee.UserID() {
+			own = append(own, attendance)
+		}
+	}
+	sort.Stable(byEventTime(own))
+
+	var streak, keptRSVPs int
+	for _, attendance := range own {
+		eventTime := attendance.Event().Time()
+		past := eventTime != nil && !eventTime.After(now)
+		if !attendance.RSVP() {
+			if !past || !Attended(attendance, checkInsTaken) {
+				stats.EventsDeclined++
+				continue
+			}
+			stats.WalkIns++
+		} else {
+			stats.EventsRSVPed++
+			if !past {
+				stats.EventsUpcoming++
+				continue
+			}
+			if !Attended(attendance, checkInsTaken) {
+				stats.NoShows++
+				streak = 0
+				continue
+			}
+			keptRSVPs++
+		}
+		stats.EventsAttended++
+		streak++
+		if streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+		if stats.FirstSeen == nil {
+			stats.FirstSeen = eventTime
+		}
+		stats.LastSeen = eventTime
+	}
+	stats.CurrentStreak = streak
+	if past := keptRSVPs + stats.NoShows; past > 0 {
+		stats.NoShowRate = float64(stats.NoShows) / float64(past)
+	}
+	return stats
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+var now = time.Date(2019, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func daysBefore(days int) *time.Time {
+	t := now.AddDate(0, 0, -days)
+	return &t
+}
+
+func attendanceAt(attendee *models.Attendee, eventTime *time.Time, rsvp bool) *models.Attendance {
+	event := models.NewEvent("Event", fmt.Sprintf("event %v", eventTime), eventTime)
+	return models.NewAttendance(attendee, event, rsvp, nil)
+}
+
+func TestComputeAttendeeStats(t *testing.T) {
+	attendee := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, daysBefore(100), false)
+	other := models.NewAttendee("Dubie", "The Dubester", "user 2", nil, nil, false)
+	attendances := []*models.Attendance{
+		attendanceAt(attendee, daysBefore(10), true),
+		attendanceAt(attendee, daysBefore(30), true),
+		attendanceAt(attendee, daysBefore(20), false),
+		attendanceAt(attendee, daysBefore(-7), true),
+		attendanceAt(other, daysBefore(5), true),
+	}
+
+	stats := ComputeAttendeeStats(attendee, attendances, CheckInsTaken(attendances), now)
+	assert.Equal(t, "user 1", stats.UserID)
+	assert.Equal(t, 3, stats.EventsRSVPed)
+	assert.Equal(t, 1, stats.EventsDeclined)
+	assert.Equal(t, 1, stats.EventsUpcoming)
+	assert.Equal(t, 2, stats.EventsAttended)
+	assert.Equal(t, 0, stats.NoShows)
+	assert.Equal(t, 2, stats.CurrentStreak)
+	assert.Equal(t, 2, stats.LongestStreak)
+	assert.Equal(t, daysBefore(30), stats.FirstSeen)
+	assert.Equal(t, daysBefore(10), stats.LastSeen)
+	if assert.NotNil(t, stats.DaysSinceJoined) {
+		assert.Equal(t, 100, *stats.DaysSinceJoined)
+	}
+}
+
+func TestComputeAttendeeStatsWithoutHistory(t *testing.T) {
+	attendee := models.NewAttendee("Alex", "", "user 1", nil, nil, false)
+	stats := ComputeAttendeeStats(attendee, nil, nil, now)
+	assert.Equal(t, 0.0, stats.NoShowRate)
+	assert.Nil(t, stats.FirstSeen)
+	assert.Nil(t, stats.DaysSinceJoined)
+}
+
+func TestComputeAttendeeStatsWithCheckIns(t *testing.T) {
+	attendee := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false)
+	attendances := []*models.Attendance{
+		attendanceAt(attendee, daysBefore(40), true).WithCheckIn(models.CheckedIn, nil, ""),
+		attendanceAt(attendee, daysBefore(30), true).WithCheckIn(models.NoShow, nil, ""),
+		attendanceAt(attendee, daysBefore(20), false).WithCheckIn(models.WalkIn, nil, ""),
+		attendanceAt(attendee, daysBefore(10), true),
+	}
+
+	stats := ComputeAttendeeStats(attendee, attendances, CheckInsTaken(attendances), now)
+	assert.Equal(t, 3, stats.EventsRSVPed)
+	assert.Equal(t, 0, stats.EventsDeclined)
+	assert.Equal(t, 3, stats.EventsAttended)
+	assert.Equal(t, 1, stats.WalkIns)
+	assert.Equal(t, 1, stats.NoShows)
+	assert.InDelta(t, 1.0/3, stats.NoShowRate, 1e-9)
+	assert.Equal(t, 2, stats.CurrentStreak)
+	assert.Equal(t, daysBefore(40), stats.FirstSeen)
+}
+
+func TestAttendedAtEventWithCheckIns(t *testing.T) {
+	event := models.NewEvent("Hack Night", "event-1", daysBefore(7))
+	checkedIn := models.NewAttendance(models.NewAttendee("Alex", "", "user 1", nil, nil, false), event, true, nil).
+		WithCheckIn(models.CheckedIn, nil, "door")
+	absent := models.NewAttendance(models.NewAttendee("Bo", "", "user 2", nil, nil, false), event, true, nil)
+	unchecked := attendanceAt(models.NewAttendee("Bo", "", "user 2", nil, nil, false), daysBefore(14), true)
+	attendances := []*models.Attendance{checkedIn, absent, unchecked}
+
+	taken := CheckInsTaken(attendances)
+	assert.Equal(t, map[string]bool{"event-1": true}, taken)
+	assert.True(t, Attended(checkedIn, taken))
+	assert.False(t, Attended(absent, taken))
+	assert.True(t, Attended(unchecked, taken))
+
+	stats := ComputeAttendeeStats(absent.Attendee(), attendances, taken, now)
+	assert.Equal(t, 2, stats.EventsRSVPed)
+	assert.Equal(t, 1, stats.EventsAttended)
+	assert.Equal(t, 1, stats.NoShows)
+}
@@ -0,0 +1,60 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// statsLess orders two AttendeeStats for a sort key.
+type statsLess func(a, b *AttendeeStats) bool
+
+func lastSeenUnix(s *AttendeeStats) int64 {
+	if s.LastSeen == nil {
+		return 0
+	}
+	return s.LastSeen.Unix()
+}
+
+// Count and rate keys sort highest first, names alphabetically.
+var statsSortKeys = map[string]statsLess{
+	"name": func(a, b *AttendeeStats) bool {
+		return strings.ToLower(a.PreferredName) < strings.ToLower(b.PreferredName)
+	},
+	"no-show-rate": func(a, b *AttendeeStats) bool { return a.NoShowRate > b.NoShowRate },
+	"no-shows":     func(a, b *AttendeeStats) bool { return a.NoShows > b.NoShows },
+	"attended":     func(a, b *AttendeeStats) bool { return a.EventsAttended > b.EventsAttended },
+	"rsvped":       func(a, b *AttendeeStats) bool { return a.EventsRSVPed > b.EventsRSVPed },
+	"streak":       func(a, b *AttendeeStats) bool { return a.CurrentStreak > b.CurrentStreak },
+	"last-seen":    func(a, b *AttendeeStats) bool { return lastSeenUnix(a) > lastSeenUnix(b) },
+}
+
+// SortKeys lists the keys accepted by SortStats.
+func SortKeys() []string {
+	keys := make([]string, 0, len(statsSortKeys))
+	for key := range statsSortKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SortStats sorts stats in place by key, breaking ties by name.
+func SortStats(stats []*AttendeeStats, key string) error {
+	less, ok := statsSortKeys[key]
+	if !ok {
+		return errors.Errorf("unknown sort key %#v", key)
+	}
+	byName := statsSortKeys["name"]
+	sort.SliceStable(stats, func(i, j int) bool {
+		if less(stats[i], stats[j]) {
+			return true
+		}
+		if less(stats[j], stats[i]) {
+			return false
+		}
+		return byName(stats[i], stats[j])
+	})
+	return nil
+}
@@ -0,0 +1,21 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortStats(t *testing.T) {
+	stats := []*AttendeeStats{
+		{PreferredName: "b", NoShowRate: 0.5},
+		{PreferredName: "c", NoShowRate: 0.75},
+		{PreferredName: "a", NoShowRate: 0.5},
+	}
+	assert.NoError(t, SortStats(stats, "no-show-rate"))
+	assert.Equal(t, "c", stats[0].PreferredName)
+	assert.Equal(t, "a", stats[1].PreferredName)
+	assert.Equal(t, "b", stats[2].PreferredName)
+
+	assert.Error(t, SortStats(stats, "shoe-size"))
+}
@@ -0,0 +1,64 @@
+// Package checkin records who actually came to an event.
+package checkin
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// CheckIn marks the attendance as having come through the door: checked in
+// if they RSVPed yes, a walk-in otherwise.
+func CheckIn(attendance *models.Attendance, at time.Time, by string) *models.Attendance {
+	status := models.CheckedIn
+	if !attendance.RSVP() {
+		status = models.WalkIn
+	}
+	return attendance.WithCheckIn(status, &at, by)
+}
+
+// NewWalkIn creates an attendee for someone with no stored record, and
+// their walk-in attendance at event.
+func NewWalkIn(event *models.Event, preferredName, legalName string, at time.Time, by string) (*models.Attendance, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	attendee := models.NewAttendee(preferredName, legalName, WalkInUserIDPrefix+id.String(), nil, nil, false)
+	return models.NewAttendance(attendee, event, false, nil).WithCheckIn(models.WalkIn, &at, by), nil
+}
+
+// WalkInUserIDPrefix starts the user IDs made up for walk-ins, who have no
+// ID from any RSVP system.
+const WalkInUserIDPrefix = "walk-in "
+
+// NormalizeName folds case, punctuation and spacing so that names written
+// differently on a sign-in sheet and in an RSVP export compare equal.
+func NormalizeName(name string) string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// NormalizeEmail folds case and drops any "+tag" from the mailbox, so that
+// the addresses someone gives on different forms compare equal.
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	mailbox, domain := email[:at], email[at:]
+	if plus := strings.Index(mailbox, "+"); plus >= 0 {
+		mailbox = mailbox[:plus]
+	}
+	return mailbox + domain
+}
@@ -0,0 +1,18 @@
+package checkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "jo anne o brien", NormalizeName("  Jo-Anne O'Brien "))
+	assert.Equal(t, "", NormalizeName(" ,. "))
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "alex@example.com", NormalizeEmail(" Alex+meetup@Example.com "))
+	assert.Equal(t, "alex@example.com", NormalizeEmail("alex@example.com"))
+	assert.Equal(t, "not an email", NormalizeEmail("Not an Email"))
+}
@@ -0,0 +1,38 @@
+package checkin
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore reports whether every character of query appears in name in
+// order, and how good a match that is. Matches at the start of words and
+// runs of consecutive characters score higher, so "amit" prefers
+// "Alex Mitchell" over "Samantha Smith".
+func FuzzyScore(query, name string) (int, bool) {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	if len(q) == 0 {
+		return 0, true
+	}
+	n := []rune(strings.ToLower(name))
+	score, qi, run := 0, 0, 0
+	for i := 0; i < len(n) && qi < len(q); i++ {
+		if n[i] != q[qi] {
+			run = 0
+			continue
+		}
+		score++
+		if i == 0 || !unicode.IsLetter(n[i-1]) && !unicode.IsDigit(n[i-1]) {
+			score += 10
+		}
+		if run > 0 {
+			score += 5
+		}
+		run++
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
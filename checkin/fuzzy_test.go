@@ -0,0 +1,25 @@
+package checkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := FuzzyScore("xyz", "Alex Mitchell")
+	assert.False(t, ok)
+
+	score, ok := FuzzyScore("", "Alex Mitchell")
+	assert.True(t, ok)
+	assert.Equal(t, 0, score)
+
+	alex, ok := FuzzyScore("amit", "Alex Mitchell")
+	assert.True(t, ok)
+	sam, ok := FuzzyScore("amit", "Samantha Smith")
+	assert.True(t, ok)
+	assert.True(t, alex > sam)
+
+	spaced, _ := FuzzyScore("a mit", "Alex Mitchell")
+	assert.Equal(t, alex, spaced)
+}
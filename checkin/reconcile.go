@@ -0,0 +1,171 @@
+package checkin
+
+import (
+	"time"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// SignIn is one line of a sign-in sheet. Any of the identifying fields may
+// be blank.
+type SignIn struct {
+	Name      string
+	LegalName string
+	UserID    string
+	Email     string
+	Time      *time.Time
+}
+
+// DisplayName is the most specific name the sign-in has.
+func (s *SignIn) DisplayName() string {
+	if s.LegalName != "" {
+		return s.LegalName
+	}
+	if s.Name != "" {
+		return s.Name
+	}
+	if s.Email != "" {
+		return s.Email
+	}
+	return s.UserID
+}
+
+// Reconciliation is the outcome of matching a sign-in sheet against an
+// event's attendances.
+type Reconciliation struct {
+	// Attendances holds every attendance to store, new walk-ins included.
+	Attendances []*models.Attendance
+	// NewAttendees are walk-ins nobody had a record for.
+	NewAttendees []*models.Attendee
+	CheckedIn    int
+	WalkIns      int
+	NoShows      int
+	// Ambiguous sign-ins matched more than one person and were skipped.
+	Ambiguous []*SignIn
+}
+
+// candidates finds the attendees a sign-in could be, trying the user ID,
+// then the email address, then the legal name, then the preferred name.
+func candidates(signIn *SignIn, attendees []*models.Attendee) []*models.Attendee {
+	type key func(a *models.Attendee) string
+	tries := []struct {
+		value string
+		key   key
+	}{
+		{signIn.UserID, (*models.Attendee).UserID},
+		{NormalizeEmail(signIn.Email), func(a *models.Attendee) string { return NormalizeEmail(a.Email()) }},
+		{NormalizeName(signIn.LegalName), func(a *models.Attendee) string { return NormalizeName(a.LegalName()) }},
+		{NormalizeName(signIn.Name), func(a *models.Attendee) string { return NormalizeName(a.PreferredName()) }},
+		{NormalizeName(signIn.Name), func(a *models.Attendee) string { return NormalizeName(a.LegalName()) }},
+	}
+	for _, try := range tries {
+		if try.value == "" {
+			continue
+		}
+		var matches []*models.Attendee
+		for _, attendee := range attendees {
+			if try.key(attendee) == try.value {
+				matches = append(matches, attendee)
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+// unmatchedKey identifies a sign-in that matched nobody, so repeats of it
+// on the sheet make one walk-in: its user ID, else its email address, else
+// its names.
+func unmatchedKey(signIn *SignIn) string {
+	if signIn.UserID != "" {
+		return "id " + signIn.UserID
+	}
+	if email := NormalizeEmail(signIn.Email); email != "" {
+		return "email " + email
+	}
+	return "name " + NormalizeName(signIn.LegalName) + "\x00" + NormalizeName(signIn.Name)
+}
+
+// Reconcile matches a sign-in sheet against the event's stored attendances.
+// Sign-ins matching an attendance check it in; ones matching another known
+// attendee, or nobody, become walk-ins, keeping the sheet's user ID if it
+// has one. Repeated sign-ins for the same person count once. Yes RSVPs nobody signed in for are
+// marked as no-shows, unless they were already checked in some other way.
+func Reconcile(event *models.Event, attendances []*models.Attendance, known []*models.Attendee, signIns []*SignIn, now time.Time, by string) (*Reconciliation, error) {
+	r := &Reconciliation{}
+	byUserID := map[string]*models.Attendance{}
+	var rsvped []*models.Attendee
+	for _, attendance := range attendances {
+		byUserID[attendance.Attendee().UserID()] = attendance
+		rsvped = append(rsvped, attendance.Attendee())
+	}
+
+	seen := map[string]bool{}
+	seenUnmatched := map[string]bool{}
+	for _, signIn := range signIns {
+		at := now
+		if signIn.Time != nil {
+			at = *signIn.Time
+		}
+		matches := candidates(signIn, rsvped)
+		if len(matches) == 0 {
+			matches = candidates(signIn, known)
+		}
+		if len(matches) > 1 {
+			r.Ambiguous = append(r.Ambiguous, signIn)
+			continue
+		}
+
+		var attendance *models.Attendance
+		if len(matches) == 1 {
+			attendee := matches[0]
+			if seen[attendee.UserID()] {
+				continue
+			}
+			seen[attendee.UserID()] = true
+			existing, ok := byUserID[attendee.UserID()]
+			if !ok {
+				existing = models.NewAttendance(attendee, event, false, nil)
+			}
+			attendance = CheckIn(existing, at, by)
+		} else {
+			key := unmatchedKey(signIn)
+			if seenUnmatched[key] {
+				continue
+			}
+			seenUnmatched[key] = true
+			walkIn, err := NewWalkIn(event, signIn.Name, signIn.LegalName, at, by)
+			if err != nil {
+				return nil, err
+			}
+			attendee := walkIn.Attendee()
+			if signIn.UserID != "" {
+				attendee = models.NewAttendee(attendee.PreferredName(), attendee.LegalName(), signIn.UserID, nil, nil, false)
+			}
+			if signIn.Email != "" {
+				attendee = attendee.WithEmail(signIn.Email)
+			}
+			walkIn = models.NewAttendance(attendee, event, false, nil).
+				WithCheckIn(walkIn.CheckInStatus(), walkIn.CheckInTime(), walkIn.CheckedInBy())
+			attendance = walkIn
+			r.NewAttendees = append(r.NewAttendees, walkIn.Attendee())
+		}
+		if attendance.CheckInStatus() == models.WalkIn {
+			r.WalkIns++
+		} else {
+			r.CheckedIn++
+		}
+		r.Attendances = append(r.Attendances, attendance)
+	}
+
+	for _, attendance := range attendances {
+		if !attendance.RSVP() || seen[attendance.Attendee().UserID()] || attendance.CameInPerson() {
+			continue
+		}
+		r.NoShows++
+		r.Attendances = append(r.Attendances, attendance.WithCheckIn(models.NoShow, nil, by))
+	}
+	return r, nil
+}
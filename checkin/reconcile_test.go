@@ -0,0 +1,126 @@
+package checkin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2019, time.March, 14, 19, 0, 0, 0, time.UTC)
+	signedInAt := now.Add(-15 * time.Minute)
+	event := models.NewEvent("Hack Night", "event-1", &now)
+	alex := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, true)
+	dubie := models.NewAttendee("Dubie", "The Dubester", "user 2", nil, nil, false)
+	sam := models.NewAttendee("Sam", "Sam Walker", "user 3", nil, nil, false)
+	declined := models.NewAttendee("Jo", "Jo Adams", "user 4", nil, nil, false)
+	regular := models.NewAttendee("Robin", "Robin Banks", "user 5", nil, nil, false)
+	attendances := []*models.Attendance{
+		models.NewAttendance(alex, event, true, nil),
+		models.NewAttendance(dubie, event, true, nil),
+		models.NewAttendance(sam, event, true, nil),
+		models.NewAttendance(declined, event, false, nil),
+	}
+	known := []*models.Attendee{alex, dubie, sam, declined, regular}
+	signIns := []*SignIn{
+		{LegalName: "alex  mitchell"},
+		{UserID: "user 2", Time: &signedInAt},
+		{Name: "Jo"},
+		{Name: "Robin"},
+		{Name: "Stranger", LegalName: "Some Stranger"},
+		{UserID: "user 1"},
+	}
+
+	r, err := Reconcile(event, attendances, known, signIns, now, "sheet")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, r.CheckedIn)
+	assert.Equal(t, 3, r.WalkIns)
+	assert.Equal(t, 1, r.NoShows)
+	assert.Empty(t, r.Ambiguous)
+	if assert.Len(t, r.NewAttendees, 1) {
+		assert.Equal(t, "Some Stranger", r.NewAttendees[0].LegalName())
+		assert.True(t, strings.HasPrefix(r.NewAttendees[0].UserID(), WalkInUserIDPrefix))
+	}
+
+	statuses := map[string]models.CheckInStatus{}
+	for _, a := range r.Attendances {
+		statuses[a.Attendee().PreferredName()] = a.CheckInStatus()
+		assert.Equal(t, "sheet", a.CheckedInBy())
+		if a.Attendee().UserID() == "user 2" {
+			assert.Equal(t, signedInAt, *a.CheckInTime())
+		}
+	}
+	assert.Equal(t, map[string]models.CheckInStatus{
+		"Alex":     models.CheckedIn,
+		"Dubie":    models.CheckedIn,
+		"Jo":       models.WalkIn,
+		"Robin":    models.WalkIn,
+		"Stranger": models.WalkIn,
+		"Sam":      models.NoShow,
+	}, statuses)
+}
+
+func TestReconcileSkipsAmbiguousNames(t *testing.T) {
+	event := models.NewEvent("Hack Night", "event-1", nil)
+	known := []*models.Attendee{
+		models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false),
+		models.NewAttendee("Alex", "Alex Smith", "user 2", nil, nil, false),
+	}
+	r, err := Reconcile(event, nil, known, []*SignIn{{Name: "Alex"}}, time.Now(), "sheet")
+	assert.NoError(t, err)
+	assert.Len(t, r.Ambiguous, 1)
+	assert.Empty(t, r.Attendances)
+}
+
+func TestReconcileMatchesEmails(t *testing.T) {
+	event := models.NewEvent("Hack Night", "event-1", nil)
+	known := []*models.Attendee{
+		models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false).WithEmail("alex@example.com"),
+		models.NewAttendee("Alex", "Alex Smith", "user 2", nil, nil, false),
+	}
+	signIns := []*SignIn{
+		{Name: "Alex", Email: "ALEX+hacknight@example.com"},
+		{Email: "sam@example.com"},
+	}
+	r, err := Reconcile(event, nil, known, signIns, time.Now(), "sheet")
+	assert.NoError(t, err)
+	assert.Empty(t, r.Ambiguous)
+	if assert.Len(t, r.Attendances, 2) {
+		assert.Equal(t, "user 1", r.Attendances[0].Attendee().UserID())
+		assert.Equal(t, "sam@example.com", r.Attendances[1].Attendee().Email())
+	}
+	if assert.Len(t, r.NewAttendees, 1) {
+		assert.Equal(t, "sam@example.com", r.NewAttendees[0].Email())
+	}
+}
+
+func TestReconcileKeepsSheetUserIDs(t *testing.T) {
+	event := models.NewEvent("Hack Night", "event-1", nil)
+	r, err := Reconcile(event, nil, nil, []*SignIn{{Name: "Sam", UserID: "user 9"}}, time.Now(), "sheet")
+	assert.NoError(t, err)
+	if assert.Len(t, r.NewAttendees, 1) {
+		assert.Equal(t, "user 9", r.NewAttendees[0].UserID())
+		assert.Equal(t, "Sam", r.NewAttendees[0].PreferredName())
+	}
+}
+
+func TestReconcileSkipsRepeatedWalkIns(t *testing.T) {
+	event := models.NewEvent("Hack Night", "event-1", nil)
+	signIns := []*SignIn{
+		{Name: "Sam Walker"},
+		{Name: "sam  walker"},
+		{Name: "Jo", Email: "jo@example.com"},
+		{Name: "Jo A.", Email: "JO@example.com"},
+		{Name: "Robin", UserID: "user 9"},
+		{Name: "Robin B", UserID: "user 9"},
+	}
+	r, err := Reconcile(event, nil, nil, signIns, time.Now(), "sheet")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, r.WalkIns)
+	assert.Len(t, r.Attendances, 3)
+	assert.Len(t, r.NewAttendees, 3)
+}
@@ -0,0 +1,245 @@
+package checkin
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+// Counts is the running headcount at the door.
+type Counts struct {
+	RSVPs     int `json:"rsvps"`
+	CheckedIn int `json:"checked_in"`
+	WalkIns   int `json:"walk_ins"`
+	// Present is everyone through the door, walk-ins included.
+	Present int `json:"present"`
+}
+
+// Session is a check-in desk for one event. Every change is written to
+// storage before it returns, so a crash loses nothing. A Session is safe
+// for concurrent use.
+type Session struct {
+	mu          sync.Mutex
+	store       storage.Store
+	event       *models.Event
+	by          string
+	now         func() time.Time
+	attendances []*models.Attendance
+}
+
+// NewSession loads the event's attendances for checking in. by names whoever
+// is running the desk and is recorded with every check-in.
+func NewSession(store storage.Store, eventID, by string) (*Session, error) {
+	event, err := store.FetchEvent(eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting event from storage")
+	}
+	s := &Session{store: store, event: event, by: by, now: time.Now}
+	return s, s.Reload()
+}
+
+func (s *Session) Event() *models.Event {
+	return s.event
+}
+
+// Reload rereads the attendances from storage, picking up check-ins made
+// at other desks.
+func (s *Session) Reload() error {
+	attendances, err := s.store.GetAttendancesForEvent(s.event.ID())
+	if err != nil {
+		return errors.Wrap(err, "error getting attendances from storage")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attendances = attendances
+	return nil
+}
+
+// Counts tallies the current headcount.
+func (s *Session) Counts() Counts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var c Counts
+	for _, attendance := range s.attendances {
+		if attendance.RSVP() {
+			c.RSVPs++
+		}
+		switch attendance.CheckInStatus() {
+		case models.CheckedIn:
+			c.CheckedIn++
+		case models.WalkIn:
+			c.WalkIns++
+		}
+	}
+	c.Present = c.CheckedIn + c.WalkIns
+	return c
+}
+
+type searchResult struct {
+	attendance *models.Attendance
+	score      int
+}
+
+// Search finds attendances whose preferred or legal name fuzzily matches
+// query, best matches first. People who RSVPed no are only included when
+// the query matches them, since they might walk in anyway.
+func (s *Session) Search(query string) []*models.Attendance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blank := strings.TrimSpace(query) == ""
+	var results []searchResult
+	for _, attendance := range s.attendances {
+		if blank && !attendance.RSVP() && !attendance.CameInPerson() {
+			continue
+		}
+		preferred, okPreferred := FuzzyScore(query, attendance.Attendee().PreferredName())
+		legal, okLegal := FuzzyScore(query, attendance.Attendee().LegalName())
+		if !okPreferred && !okLegal {
+			continue
+		}
+		if legal > preferred {
+			preferred = legal
+		}
+		results = append(results, searchResult{attendance, preferred})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return strings.ToLower(results[i].attendance.Attendee().PreferredName()) <
+			strings.ToLower(results[j].attendance.Attendee().PreferredName())
+	})
+	matches := make([]*models.Attendance, len(results))
+	for i, result := range results {
+		matches[i] = result.attendance
+	}
+	return matches
+}
+
+func (s *Session) find(userID string) (int, error) {
+	for i, attendance := range s.attendances {
+		if attendance.Attendee().UserID() == userID {
+			return i, nil
+		}
+	}
+	return 0, errors.Wrapf(storage.ErrNoAttendanceEntry, "%#v has no attendance for this event", userID)
+}
+
+func (s *Session) save(i int, attendance *models.Attendance) (*models.Attendance, error) {
+	if _, err := s.store.UpsertAttendance(attendance); err != nil {
+		return nil, errors.Wrap(err, "error saving check-in")
+	}
+	s.attendances[i] = attendance
+	return attendance, nil
+}
+
+// CheckIn records the attendee as having arrived. Checking someone in
+// twice keeps the first check-in, even one made at another desk since the
+// session loaded: the attendance is reread and saved in one transaction.
+func (s *Session) CheckIn(userID string) (*models.Attendance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.find(userID)
+	if err != nil {
+		return nil, err
+	}
+	var attendance *models.Attendance
+	err = s.store.WithTx(func(tx storage.Store) error {
+		current, err := tx.FetchAttendance(s.event.ID(), userID)
+		if err != nil {
+			return errors.Wrap(err, "error getting attendance from storage")
+		}
+		if current.CameInPerson() {
+			attendance = current
+			return nil
+		}
+		attendance = CheckIn(current, s.now(), s.by)
+		_, err = tx.UpsertAttendance(attendance)
+		return errors.Wrap(err, "error saving check-in")
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.attendances[i] = attendance
+	return attendance, nil
+}
+
+// Undo clears the attendee's check-in, for when the wrong person was
+// checked in.
+func (s *Session) Undo(userID string) (*models.Attendance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.find(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(i, s.attendances[i].WithCheckIn(models.NotCheckedIn, nil, ""))
+}
+
+// AddWalkIn registers someone with no stored record as a new attendee and
+// checks them in.
+func (s *Session) AddWalkIn(preferredName, legalName string) (*models.Attendance, error) {
+	if strings.TrimSpace(preferredName) == "" && strings.TrimSpace(legalName) == "" {
+		return nil, errors.New("a walk-in needs a name")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attendance, err := NewWalkIn(s.event, strings.TrimSpace(preferredName), strings.TrimSpace(legalName), s.now(), s.by)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating walk-in")
+	}
+	err = s.store.WithTx(func(tx storage.Store) error {
+		if _, err := tx.UpsertAttendee(attendance.Attendee()); err != nil {
+			return errors.Wrap(err, "error saving walk-in attendee")
+		}
+		if _, err := tx.UpsertAttendance(attendance); err != nil {
+			return errors.Wrap(err, "error saving walk-in check-in")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.attendances = append(s.attendances, attendance)
+	return attendance, nil
+}
+
+// Close ends check-in for the event, marking everyone who RSVPed yes but
+// hasn't been checked in as a no-show, as Reconcile does. It rereads the
+// attendances first so check-ins made at other desks are kept, and
+// returns how many were marked.
+func (s *Session) Close() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var attendances []*models.Attendance
+	noShows := 0
+	err := s.store.WithTx(func(tx storage.Store) error {
+		var err error
+		attendances, err = tx.GetAttendancesForEvent(s.event.ID())
+		if err != nil {
+			return errors.Wrap(err, "error getting attendances from storage")
+		}
+		for i, attendance := range attendances {
+			if !attendance.RSVP() || attendance.CheckInStatus() != models.NotCheckedIn {
+				continue
+			}
+			attendances[i] = attendance.WithCheckIn(models.NoShow, nil, s.by)
+			if _, err := tx.UpsertAttendance(attendances[i]); err != nil {
+				return errors.Wrap(err, "error saving no-show")
+			}
+			noShows++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.attendances = attendances
+	return noShows, nil
+}
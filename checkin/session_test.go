@@ -0,0 +1,132 @@
+package checkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func newTestSession(t *testing.T) (*Session, *memory.MemoryStorage) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	_, err := store.UpsertEvent(event)
+	assert.NoError(t, err)
+	for _, a := range []struct {
+		preferred, legal, userID string
+		rsvp                     bool
+	}{
+		{"Alex", "Alex Mitchell", "user 1", true},
+		{"Dubie", "The Dubester", "user 2", true},
+		{"Jo", "Jo Adams", "user 3", false},
+	} {
+		attendee := models.NewAttendee(a.preferred, a.legal, a.userID, nil, nil, false)
+		_, err := store.UpsertAttendee(attendee)
+		assert.NoError(t, err)
+		_, err = store.UpsertAttendance(models.NewAttendance(attendee, event, a.rsvp, nil))
+		assert.NoError(t, err)
+	}
+	session, err := NewSession(store, "event-1", "door")
+	assert.NoError(t, err)
+	session.now = func() time.Time { return eventTime }
+	return session, store
+}
+
+func TestSessionSearch(t *testing.T) {
+	session, _ := newTestSession(t)
+	all := session.Search("")
+	assert.Len(t, all, 2, "people who RSVPed no are hidden until searched for")
+	matches := session.Search("jo")
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "user 3", matches[0].Attendee().UserID())
+	}
+	matches = session.Search("dubester")
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "user 2", matches[0].Attendee().UserID())
+	}
+}
+
+func TestSessionCheckIn(t *testing.T) {
+	session, store := newTestSession(t)
+	attendance, err := session.CheckIn("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CheckedIn, attendance.CheckInStatus())
+	_, err = session.CheckIn("user 3")
+	assert.NoError(t, err)
+	_, err = session.CheckIn("nobody")
+	assert.Error(t, err)
+
+	stored, err := store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CheckedIn, stored.CheckInStatus())
+	assert.Equal(t, "door", stored.CheckedInBy())
+	assert.Equal(t, Counts{RSVPs: 2, CheckedIn: 1, WalkIns: 1, Present: 2}, session.Counts())
+
+	_, err = session.Undo("user 1")
+	assert.NoError(t, err)
+	stored, err = store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NotCheckedIn, stored.CheckInStatus())
+}
+
+func TestSessionAddWalkIn(t *testing.T) {
+	session, store := newTestSession(t)
+	_, err := session.AddWalkIn(" ", "")
+	assert.Error(t, err)
+
+	attendance, err := session.AddWalkIn("Sam", "Sam Walker")
+	assert.NoError(t, err)
+	stored, err := store.FetchAttendance("event-1", attendance.Attendee().UserID())
+	assert.NoError(t, err)
+	assert.Equal(t, models.WalkIn, stored.CheckInStatus())
+	assert.Equal(t, "Sam Walker", stored.Attendee().LegalName())
+	assert.Equal(t, 1, session.Counts().WalkIns)
+}
+
+func TestSessionClose(t *testing.T) {
+	session, store := newTestSession(t)
+	other, err := NewSession(store, "event-1", "side door")
+	assert.NoError(t, err)
+	_, err = other.CheckIn("user 1")
+	assert.NoError(t, err)
+
+	noShows, err := session.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, noShows)
+	attendances, err := store.GetAttendancesForEvent("event-1")
+	assert.NoError(t, err)
+	statuses := map[string]models.CheckInStatus{}
+	for _, attendance := range attendances {
+		statuses[attendance.Attendee().UserID()] = attendance.CheckInStatus()
+	}
+	assert.Equal(t, map[string]models.CheckInStatus{
+		"user 1": models.CheckedIn,
+		"user 2": models.NoShow,
+		"user 3": models.NotCheckedIn,
+	}, statuses)
+	assert.Equal(t, Counts{RSVPs: 2, CheckedIn: 1, Present: 1}, session.Counts())
+
+	noShows, err = session.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, noShows)
+}
+
+func TestSessionCheckInKeepsOtherDesksCheckIn(t *testing.T) {
+	session, store := newTestSession(t)
+	other, err := NewSession(store, "event-1", "side door")
+	assert.NoError(t, err)
+	_, err = other.CheckIn("user 2")
+	assert.NoError(t, err)
+
+	attendance, err := session.CheckIn("user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "side door", attendance.CheckedInBy())
+	stored, err := store.FetchAttendance("event-1", "user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "side door", stored.CheckedInBy())
+	assert.Equal(t, 1, session.Counts().CheckedIn)
+}
@@ -3,15 +3,25 @@ package main
 import (
 	"os"
 
-	_ "github.com/mattn/go-sqlite3"
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/alexthemitchell/community-attendance/cli/commands"
+	_ "github.com/alexthemitchell/community-attendance/storage/memory"
+	_ "github.com/alexthemitchell/community-attendance/storage/sql"
 )
 
 func main() {
 	app := kingpin.New("attendance", "Event attendee forecasting software")
+	commands.AddTimeZoneFlag(app)
 	commands.AddImportSubcommand(app)
 	commands.AddListSubcommand(app)
+	commands.AddDBSubcommand(app)
+	commands.AddExportSubcommand(app)
+	commands.AddReportSubcommand(app)
+	commands.AddForecastSubcommand(app)
+	commands.AddCheckinSubcommand(app)
+	commands.AddServeSubcommand(app)
+	commands.AddAttendeesSubcommand(app)
+	commands.AddEventSubcommand(app)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 }
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/cli/output"
+	"github.com/alexthemitchell/community-attendance/dedupe"
+)
+
+type attendeesCommand struct {
+	dbFileName string
+	keepID     string
+	dropID     string
+	minScore   float64
+	output     string
+}
+
+var duplicateColumns = []output.Column{
+	{Key: "keep_id", Header: "Keep ID"},
+	{Key: "keep_name", Header: "Keep Name"},
+	{Key: "drop_id", Header: "Drop ID"},
+	{Key: "drop_name", Header: "Drop Name"},
+	{Key: "score", Header: "Score"},
+	{Key: "reasons", Header: "Reasons"},
+}
+
+func duplicatesTable(candidates []*dedupe.Candidate) *output.Table {
+	t := &output.Table{Columns: duplicateColumns}
+	for _, c := range candidates {
+		t.Rows = append(t.Rows, []interface{}{
+			c.Keep.UserID(),
+			c.Keep.PreferredName(),
+			c.Drop.UserID(),
+			c.Drop.PreferredName(),
+			math.Round(c.Score*100) / 100,
+			strings.Join(c.Reasons, ", "),
+		})
+	}
+	return t
+}
+
+func (a *attendeesCommand) duplicates(c *kingpin.ParseContext) error {
+	store, err := openStore(a.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	attendees, err := store.GetAllAttendees()
+	if err != nil {
+		return errors.Wrap(err, "error getting attendees from storage")
+	}
+	return writeOutput(a.output, duplicatesTable(dedupe.FindCandidates(attendees, a.minScore)))
+}
+
+func (a *attendeesCommand) merge(c *kingpin.ParseContext) error {
+	store, err := openStore(a.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	summary, err := dedupe.Merge(store, a.keepID, a.dropID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("merged %#v into %#v\n", a.dropID, a.keepID)
+	fmt.Printf("attendances: %d moved, %d combined\n", summary.Moved, summary.Combined)
+	fmt.Printf("now resolving to %#v: %s\n", a.keepID, strings.Join(summary.Aliases, ", "))
+	return nil
+}
+
+func AddAttendeesSubcommand(app *kingpin.Application) {
+	c := app.Command("attendees", "find and merge duplicate attendees")
+
+	ac := &attendeesCommand{}
+	d := c.Command("duplicates", "list pairs of attendees that are probably the same person").Action(ac.duplicates)
+	addStoreFlag(d, &ac.dbFileName)
+	d.Flag("min-score", "the lowest score, from 0 to 1, to list").Default(fmt.Sprint(dedupe.DefaultMinScore)).Float64Var(&ac.minScore)
+	addOutputFlag(d, &ac.output)
+
+	m := c.Command("merge", "merge one attendee into another, keeping the first").Action(ac.merge)
+	m.Arg("keep-id", "the user ID of the attendee to keep").Required().StringVar(&ac.keepID)
+	m.Arg("drop-id", "the user ID of the attendee to merge into it and remove").Required().StringVar(&ac.dropID)
+	addStoreFlag(m, &ac.dbFileName)
+}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/dedupe"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestDuplicatesTable(t *testing.T) {
+	keep := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false)
+	drop := models.NewAttendee("Alex M", "Alex Mitchell", "user 2", nil, nil, false)
+	table := duplicatesTable([]*dedupe.Candidate{{Keep: keep, Drop: drop, Score: 0.7049, Reasons: []string{"same legal name"}}})
+	assert.Equal(t, []interface{}{"user 1", "Alex", "user 2", "Alex M", 0.7, "same legal name"}, table.Rows[0])
+}
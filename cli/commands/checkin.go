@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/cli/reader"
+	"github.com/alexthemitchell/community-attendance/cli/tui"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+type checkinCommand struct {
+	dbFileName        string
+	eventID           string
+	fileName          string
+	by                string
+	sheet             string
+	legalNameQuestion string
+}
+
+func (ci *checkinCommand) importSheet(c *kingpin.ParseContext) error {
+	store, err := openStore(ci.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	var signIns []*checkin.SignIn
+	var result *checkin.Reconciliation
+	err = store.WithTx(func(tx storage.Store) error {
+		event, err := tx.FetchEvent(ci.eventID)
+		if err != nil {
+			return errors.Wrap(err, "error getting event from storage")
+		}
+		options := reader.DefaultOptions()
+		options.Columns.LegalNameQuestion = ci.legalNameQuestion
+		options.Sheet = ci.sheet
+		options.Location = event.Location(defaultLocation)
+		signIns, err = reader.ReadSignInSheet(ci.fileName, options)
+		if err != nil {
+			return errors.Wrap(err, "error reading sign-in sheet")
+		}
+		attendances, err := tx.GetAttendancesForEvent(ci.eventID)
+		if err != nil {
+			return errors.Wrap(err, "error getting attendances from storage")
+		}
+		known, err := tx.GetAllAttendees()
+		if err != nil {
+			return errors.Wrap(err, "error getting attendees from storage")
+		}
+		for _, signIn := range signIns {
+			if signIn.UserID == "" {
+				continue
+			}
+			if signIn.UserID, err = tx.ResolveUserID(signIn.UserID); err != nil {
+				return errors.Wrap(err, "error resolving merged user IDs")
+			}
+		}
+		result, err = checkin.Reconcile(event, attendances, known, signIns, time.Now(), ci.by)
+		if err != nil {
+			return errors.Wrap(err, "error reconciling sign-in sheet")
+		}
+		for _, attendee := range result.NewAttendees {
+			if _, err := tx.UpsertAttendee(attendee); err != nil {
+				return errors.Wrap(err, "error saving walk-in attendee")
+			}
+		}
+		for _, attendance := range result.Attendances {
+			if _, err := tx.UpsertAttendance(attendance); err != nil {
+				return errors.Wrap(err, "error saving check-in")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("read %d sign-ins\n", len(signIns))
+	fmt.Printf("%d checked in, %d walk-ins (%d new attendees), %d no-shows\n",
+		result.CheckedIn, result.WalkIns, len(result.NewAttendees), result.NoShows)
+	for _, signIn := range result.Ambiguous {
+		fmt.Printf("skipped %#v, who matches more than one attendee\n", signIn.DisplayName())
+	}
+	return nil
+}
+
+func (ci *checkinCommand) run(c *kingpin.ParseContext) error {
+	store, err := openStore(ci.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	session, err := checkin.NewSession(store, ci.eventID, ci.by)
+	if err != nil {
+		return err
+	}
+	if err := tui.RunCheckIn(session, defaultLocation); err != nil {
+		return err
+	}
+	counts := session.Counts()
+	fmt.Printf("%d of %d RSVPs checked in, %d walk-ins\n", counts.CheckedIn, counts.RSVPs, counts.WalkIns)
+	return nil
+}
+
+func AddCheckinSubcommand(app *kingpin.Application) {
+	c := app.Command("checkin", "record who came to an event")
+
+	ci := &checkinCommand{}
+	i := c.Command("import", "reconcile a sign-in sheet against an event's RSVPs").Action(ci.importSheet)
+	i.Arg("event-id", "the ID of the event").Required().StringVar(&ci.eventID)
+	i.Arg("file-name", "the sign-in sheet to read").Required().StringVar(&ci.fileName)
+	addStoreFlag(i, &ci.dbFileName)
+	i.Flag("by", "who recorded the check-ins").Default("sign-in sheet").StringVar(&ci.by)
+	i.Flag("sheet", "the name or 1-based position of the worksheet to read from a workbook").StringVar(&ci.sheet)
+	i.Flag("legal-name-question", "text that identifies a column holding the legal name").Default(reader.DefaultMeetupColumns.LegalNameQuestion).StringVar(&ci.legalNameQuestion)
+
+	r := c.Command("run", "check people in at the door with a full-screen terminal UI").Action(ci.run)
+	r.Arg("event-id", "the ID of the event").Required().StringVar(&ci.eventID)
+	addStoreFlag(r, &ci.dbFileName)
+	r.Flag("by", "who is checking people in").Default(defaultCheckInBy()).StringVar(&ci.by)
+}
+
+// defaultCheckInBy names the person at the terminal after the logged in
+// user.
+func defaultCheckInBy() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "door"
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+	"github.com/alexthemitchell/community-attendance/storage/sql"
+	"github.com/alexthemitchell/community-attendance/timezone"
+)
+
+type dbCommand struct {
+	dbFileName string
+	target     int
+}
+
+func openUnmigratedStorage(dsn string) (*storage.SQLStorage, error) {
+	s, err := storage.OpenUnmigrated(dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening storage %#v", dsn)
+	}
+	return s, nil
+}
+
+func (d *dbCommand) migrate(c *kingpin.ParseContext) error {
+	s, err := openUnmigratedStorage(d.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	target := d.target
+	if target == 0 {
+		target = storage.LatestSchemaVersion()
+	}
+	before, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if err := s.Migrate(target); err != nil {
+		return errors.Wrap(err, "error migrating database")
+	}
+	fmt.Printf("migrated %#v from schema version %d to %d\n", d.dbFileName, before, target)
+	return printLegacyTimesNote(s)
+}
+
+func printLegacyTimesNote(s *storage.SQLStorage) error {
+	pending, err := s.HasLegacyTimes()
+	if err != nil {
+		return err
+	}
+	if pending {
+		fmt.Println("times saved before they were stored in UTC need converting: run db localize-times")
+	}
+	return nil
+}
+
+func (d *dbCommand) status(c *kingpin.ParseContext) error {
+	s, err := openUnmigratedStorage(d.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema version %d of %d\n", current, storage.LatestSchemaVersion())
+	for _, m := range storage.Migrations() {
+		state := "pending"
+		if m.Version <= current {
+			state = "applied"
+		}
+		fmt.Printf("%4d  %-8s %s\n", m.Version, state, m.Description)
+	}
+	return printLegacyTimesNote(s)
+}
+
+// legacyTimesConverter is storage that may hold times saved before they
+// were stored in UTC.
+type legacyTimesConverter interface {
+	ConvertLegacyTimes(convert func(tx interfaces.Store) error) (bool, error)
+}
+
+func (d *dbCommand) localizeTimes(c *kingpin.ParseContext) error {
+	store, err := openStoreWithLegacyTimes(d.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	converter, ok := store.(legacyTimesConverter)
+	if !ok {
+		return errors.Errorf("%#v can't hold times saved before they were stored in UTC", d.dbFileName)
+	}
+	converted, err := converter.ConvertLegacyTimes(func(tx interfaces.Store) error {
+		return localizeLegacyTimes(tx, defaultLocation)
+	})
+	if err != nil {
+		return errors.Wrap(err, "error converting times")
+	}
+	if !converted {
+		fmt.Println("no times need converting")
+		return nil
+	}
+	fmt.Printf("converted times in %#v to UTC\n", d.dbFileName)
+	return nil
+}
+
+// localizeLegacyTimes reinterprets times saved as wall clock times labeled
+// UTC. Event and RSVP times are read in the event's time zone, or location
+// if it has none; check-in times, which were the clock of whoever checked
+// people in, are read in location.
+func localizeLegacyTimes(tx interfaces.Store, location *time.Location) error {
+	events, err := tx.GetAllEvents()
+	if err != nil {
+		return errors.Wrap(err, "error getting events from storage")
+	}
+	for _, event := range events {
+		eventLocation := event.Location(location)
+		converted := models.NewEvent(event.Name(), event.ID(), legacyTime(event.Time(), eventLocation)).WithDetails(event.Details())
+		if _, err := tx.UpsertEvent(converted); err != nil {
+			return errors.Wrapf(err, "error saving event %#v", event.ID())
+		}
+		attendances, err := tx.GetAttendancesForEvent(event.ID())
+		if err != nil {
+			return errors.Wrap(err, "error getting attendances from storage")
+		}
+		for _, a := range attendances {
+			a = models.NewAttendance(a.Attendee(), converted, a.RSVP(), legacyTime(a.RSVPTime(), eventLocation)).
+				WithCheckIn(a.CheckInStatus(), legacyTime(a.CheckInTime(), location), a.CheckedInBy()).
+				WithCancelled(a.Cancelled())
+			if _, err := tx.UpsertAttendance(a); err != nil {
+				return errors.Wrapf(err, "error saving attendance of %#v", a.Attendee().UserID())
+			}
+		}
+	}
+	return nil
+}
+
+// legacyTime reads the wall clock of t in location. A time clocks skipped
+// is left to time.Date to place rather than failing the whole conversion.
+func legacyTime(t *time.Time, location *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local, err := timezone.Date(*t, location)
+	if err != nil {
+		local = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+	}
+	return &local
+}
+
+func AddDBSubcommand(app *kingpin.Application) {
+	c := app.Command("db", "inspect and migrate the database schema")
+
+	dc := &dbCommand{}
+	m := c.Command("migrate", "apply pending schema migrations").Action(dc.migrate)
+	m.Arg("db-file-name", "the storage DSN or name of the sqlite db file").Required().StringVar(&dc.dbFileName)
+	m.Flag("to", "the schema version to migrate to (defaults to the latest)").IntVar(&dc.target)
+
+	s := c.Command("status", "show the schema version and pending migrations").Action(dc.status)
+	s.Arg("db-file-name", "the storage DSN or name of the sqlite db file").Required().StringVar(&dc.dbFileName)
+
+	l := c.Command("localize-times", "convert times saved before they were stored in UTC, reading them in each event's time zone or --default-time-zone").Action(dc.localizeTimes)
+	l.Arg("db-file-name", "the storage DSN or name of the sqlite db file").Required().StringVar(&dc.dbFileName)
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+	"github.com/alexthemitchell/community-attendance/storage/sql"
+)
+
+func TestLocalizeLegacyTimes(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	// Wall clock times labeled UTC, as they were saved before.
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	rsvpTime := time.Date(2019, time.March, 1, 9, 0, 0, 0, time.UTC)
+	checkInTime := time.Date(2019, time.March, 14, 21, 35, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime).WithDetails(models.EventDetails{TimeZone: "America/Los_Angeles"})
+	attendee := models.NewAttendee("Alex", "", "user-1", &url.URL{}, nil, false)
+	_, err = store.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = store.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	_, err = store.UpsertAttendance(models.NewAttendance(attendee, event, true, &rsvpTime).WithCheckIn(models.CheckedIn, &checkInTime, "door"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, localizeLegacyTimes(store, newYork))
+
+	fetched, err := store.FetchEvent("event-1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), fetched.Time().UTC())
+	assert.Equal(t, "America/Los_Angeles", fetched.Details().TimeZone)
+	attendance, err := store.FetchAttendance("event-1", "user-1")
+	assert.NoError(t, err)
+	// RSVPs were made before clocks sprang forward, check-ins after.
+	assert.Equal(t, time.Date(2019, time.March, 1, 17, 0, 0, 0, time.UTC), attendance.RSVPTime().UTC())
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 35, 0, 0, time.UTC), attendance.CheckInTime().UTC())
+	assert.Equal(t, models.CheckedIn, attendance.CheckInStatus())
+}
+
+func TestLegacyTimesBlockNewWritesUntilConverted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attendance-db")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dsn := filepath.Join(dir, "test.db")
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+	defer func(location *time.Location) { defaultLocation = location }(defaultLocation)
+	defaultLocation = losAngeles
+
+	// A database from before times were stored in UTC, holding a wall
+	// clock time labeled UTC.
+	old, err := storage.OpenUnmigrated(dsn)
+	assert.NoError(t, err)
+	assert.NoError(t, old.Migrate(7))
+	old.Close()
+	db, err := sql.Open("sqlite3", dsn)
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO events(name, time, id) VALUES (?,?,?)", "Hack Night", "2019-03-14T18:30:00Z", "old")
+	assert.NoError(t, err)
+	db.Close()
+
+	_, err = openStore(dsn)
+	if assert.Error(t, err, "nothing may be written next to unconverted times") {
+		assert.Contains(t, err.Error(), "db localize-times")
+	}
+	d := &dbCommand{dbFileName: dsn}
+	assert.NoError(t, d.localizeTimes(nil))
+
+	store, err := openStore(dsn)
+	assert.NoError(t, err)
+	newTime := time.Date(2019, time.April, 11, 1, 30, 0, 0, time.UTC)
+	_, err = store.UpsertEvent(models.NewEvent("Hack Night", "new", &newTime))
+	assert.NoError(t, err)
+	store.Close()
+	// Converting again leaves the new, correct time alone.
+	assert.NoError(t, d.localizeTimes(nil))
+
+	store, err = openStore(dsn)
+	assert.NoError(t, err)
+	defer store.Close()
+	event, err := store.FetchEvent("old")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), event.Time().UTC())
+	event, err = store.FetchEvent("new")
+	assert.NoError(t, err)
+	assert.Equal(t, newTime, event.Time().UTC())
+}
@@ -0,0 +1,289 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/cli/output"
+	"github.com/alexthemitchell/community-attendance/models"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
+	"github.com/alexthemitchell/community-attendance/timezone"
+)
+
+// eventFlags hold the event fields that can be set on the command line.
+type eventFlags struct {
+	name     string
+	time     string
+	timeZone string
+	duration time.Duration
+	venue    string
+	address  string
+	capacity int
+	group    string
+	series   string
+	sourceID string
+}
+
+func addEventFlags(c *kingpin.CmdClause, f *eventFlags) {
+	c.Flag("name", "the name of the event").StringVar(&f.name)
+	c.Flag("time", "when the event starts in its time zone or --default-time-zone, such as \"2019-03-14 18:30\" or \"next Tuesday 6:30pm\"").StringVar(&f.time)
+	c.Flag("time-zone", "the IANA time zone of the event, such as America/Los_Angeles; without --time, the event keeps its clock time in the new zone").StringVar(&f.timeZone)
+	c.Flag("duration", "how long the event lasts, such as 2h30m").DurationVar(&f.duration)
+	c.Flag("venue", "the name of the venue").StringVar(&f.venue)
+	c.Flag("address", "the address of the venue").StringVar(&f.address)
+	c.Flag("capacity", "how many people the venue holds").IntVar(&f.capacity)
+	c.Flag("group", "the organizing group").StringVar(&f.group)
+	c.Flag("series", "the ID of the recurring series the event belongs to").StringVar(&f.series)
+	c.Flag("source-id", "the event's ID in the system it's imported from, such as its Meetup event ID").StringVar(&f.sourceID)
+}
+
+// flagsSet returns the names of the flags given on the command line.
+func flagsSet(c *kingpin.ParseContext) map[string]bool {
+	set := map[string]bool{}
+	for _, element := range c.Elements {
+		if flag, ok := element.Clause.(*kingpin.FlagClause); ok {
+			set[flag.Model().Name] = true
+		}
+	}
+	return set
+}
+
+// apply returns a copy of event with the fields of the flags in set
+// replaced.
+func (f *eventFlags) apply(event *models.Event, set map[string]bool) (*models.Event, error) {
+	name, eventTime, details := event.Name(), event.Time(), event.Details()
+	if set["name"] {
+		name = f.name
+	}
+	if set["time-zone"] {
+		location, err := loadLocation(f.timeZone)
+		if err != nil {
+			return nil, err
+		}
+		// The event keeps the clock time it had in its old zone.
+		if eventTime != nil && !set["time"] {
+			t, err := timezone.Date(eventTime.In(event.Location(defaultLocation)), location)
+			if err != nil {
+				return nil, errors.Wrap(err, "error moving the event to --time-zone")
+			}
+			eventTime = &t
+		}
+		details.TimeZone = f.timeZone
+	}
+	if set["time"] {
+		t, err := parseTimeFlag("time", f.time, event.WithDetails(details).Location(defaultLocation))
+		if err != nil {
+			return nil, err
+		}
+		eventTime = &t
+	}
+	if set["duration"] {
+		if f.duration < 0 {
+			return nil, errors.New("--duration must not be negative")
+		}
+		details.Duration = f.duration
+	}
+	if set["capacity"] {
+		if f.capacity < 0 {
+			return nil, errors.New("--capacity must not be negative")
+		}
+		details.Capacity = f.capacity
+	}
+	if set["venue"] {
+		details.VenueName = f.venue
+	}
+	if set["address"] {
+		details.VenueAddress = f.address
+	}
+	if set["group"] {
+		details.Group = f.group
+	}
+	if set["series"] {
+		details.SeriesID = f.series
+	}
+	if set["source-id"] {
+		details.SourceID = f.sourceID
+	}
+	return models.NewEvent(name, event.ID(), eventTime).WithDetails(details), nil
+}
+
+var eventDetailColumns = []output.Column{
+	{Key: "id", Header: "ID"},
+	{Key: "name", Header: "Event"},
+	{Key: "time", Header: "Time", Layout: eventTimeDisplayFormat},
+	{Key: "time_zone", Header: "Time Zone"},
+	{Key: "duration", Header: "Duration"},
+	{Key: "venue_name", Header: "Venue"},
+	{Key: "venue_address", Header: "Address"},
+	{Key: "capacity", Header: "Capacity"},
+	{Key: "group", Header: "Group"},
+	{Key: "series_id", Header: "Series"},
+	{Key: "source_id", Header: "Source ID"},
+}
+
+// eventDetailsTable lays out every field of an event. The table format
+// lists one field per row, since a single row would be too wide to read.
+func eventDetailsTable(event *models.Event, format string) *output.Table {
+	d := event.Details()
+	var duration, capacity interface{}
+	if d.Duration > 0 {
+		duration = d.Duration.String()
+	}
+	if d.Capacity > 0 {
+		capacity = d.Capacity
+	}
+	row := []interface{}{event.ID(), event.Name(), event.Time(), d.TimeZone, duration, d.VenueName, d.VenueAddress, capacity, d.Group, d.SeriesID, d.SourceID}
+	if format != "table" {
+		return &output.Table{Columns: eventDetailColumns, Rows: [][]interface{}{row}}
+	}
+	t := &output.Table{Columns: []output.Column{{Key: "field", Header: "Field"}, {Key: "value", Header: "Value"}}}
+	for i, column := range eventDetailColumns {
+		value := row[i]
+		if eventTime, ok := value.(*time.Time); ok && eventTime != nil {
+			value = eventTime.Format(column.Layout)
+		}
+		t.Rows = append(t.Rows, []interface{}{column.Header, value})
+	}
+	return t
+}
+
+var eventImportColumns = []output.Column{
+	{Key: "imported_at", Header: "Imported", Layout: eventTimeDisplayFormat},
+	{Key: "file_name", Header: "File"},
+	{Key: "records", Header: "Records"},
+	{Key: "sha256", Header: "SHA-256"},
+}
+
+func eventImportsTable(imports []*models.Import, location *time.Location) *output.Table {
+	t := &output.Table{Columns: eventImportColumns}
+	for _, record := range imports {
+		t.Rows = append(t.Rows, []interface{}{record.ImportedAt().In(location), record.FileName(), record.Records(), record.SHA256()})
+	}
+	return t
+}
+
+type eventCommand struct {
+	dbFileName string
+	eventID    string
+	flags      eventFlags
+	output     string
+}
+
+func (e *eventCommand) create(c *kingpin.ParseContext) error {
+	set := flagsSet(c)
+	if !set["name"] || !set["time"] {
+		return errors.New("--name and --time are required")
+	}
+	id := e.eventID
+	if id == "" {
+		generated, err := uuid.NewRandom()
+		if err != nil {
+			return errors.Wrap(err, "unable to create random UUID for event")
+		}
+		id = generated.String()
+	}
+	event, err := e.flags.apply(models.NewEvent("", id, nil), set)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(e.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	err = store.WithTx(func(tx storage.Store) error {
+		if _, err := tx.FetchEvent(id); err == nil {
+			return errors.Errorf("an event with ID %#v already exists", id)
+		}
+		_, err := tx.UpsertEvent(event)
+		return errors.Wrap(err, "error saving event")
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(id)
+	return nil
+}
+
+func (e *eventCommand) edit(c *kingpin.ParseContext) error {
+	store, err := openStore(e.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	return store.WithTx(func(tx storage.Store) error {
+		event, err := tx.FetchEvent(e.eventID)
+		if err != nil {
+			return errors.Wrap(err, "error getting event from storage")
+		}
+		event, err = e.flags.apply(event, flagsSet(c))
+		if err != nil {
+			return err
+		}
+		result, err := tx.UpsertEvent(event)
+		if err != nil {
+			return errors.Wrap(err, "error saving event")
+		}
+		fmt.Printf("event: %s\n", result)
+		return nil
+	})
+}
+
+func (e *eventCommand) show(c *kingpin.ParseContext) error {
+	store, err := openStore(e.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	event, err := store.FetchEvent(e.eventID)
+	if err != nil {
+		return errors.Wrap(err, "error getting event from storage")
+	}
+	return output.Write(os.Stdout, e.output, eventDetailsTable(event.Local(defaultLocation), e.output), output.IsTerminal(os.Stdout))
+}
+
+func (e *eventCommand) imports(c *kingpin.ParseContext) error {
+	store, err := openStore(e.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if _, err := store.FetchEvent(e.eventID); err != nil {
+		return errors.Wrap(err, "error getting event from storage")
+	}
+	imports, err := store.GetImportsForEvent(e.eventID)
+	if err != nil {
+		return errors.Wrap(err, "error getting imports from storage")
+	}
+	return output.Write(os.Stdout, e.output, eventImportsTable(imports, defaultLocation), output.IsTerminal(os.Stdout))
+}
+
+func AddEventSubcommand(app *kingpin.Application) {
+	c := app.Command("event", "create, edit and show events and their import history")
+
+	ec := &eventCommand{}
+	create := c.Command("create", "create an event, printing its ID").Action(ec.create)
+	create.Flag("id", "the ID for the event; a random UUID if not given").StringVar(&ec.eventID)
+	addEventFlags(create, &ec.flags)
+	addStoreFlag(create, &ec.dbFileName)
+
+	edit := c.Command("edit", "change the fields of an event given as flags").Action(ec.edit)
+	edit.Arg("event-id", "the ID of the event").Required().StringVar(&ec.eventID)
+	addEventFlags(edit, &ec.flags)
+	addStoreFlag(edit, &ec.dbFileName)
+
+	show := c.Command("show", "show every field of an event").Action(ec.show)
+	show.Arg("event-id", "the ID of the event").Required().StringVar(&ec.eventID)
+	addStoreFlag(show, &ec.dbFileName)
+	addOutputFlag(show, &ec.output)
+
+	imports := c.Command("imports", "list the files imported into an event, oldest first").Action(ec.imports)
+	imports.Arg("event-id", "the ID of the event").Required().StringVar(&ec.eventID)
+	addStoreFlag(imports, &ec.dbFileName)
+	addOutputFlag(imports, &ec.output)
+}
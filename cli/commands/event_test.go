@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestEventFlagsApply(t *testing.T) {
+	flags := &eventFlags{name: "Hack Night", time: "2019-03-14 18:30", timeZone: "America/Los_Angeles", capacity: 40, venue: "The Library"}
+	event, err := flags.apply(models.NewEvent("", "event-1", nil), map[string]bool{"name": true, "time": true, "time-zone": true, "capacity": true, "venue": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", event.ID())
+	assert.Equal(t, "Hack Night", event.Name())
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), event.Time().UTC())
+	assert.Equal(t, models.EventDetails{VenueName: "The Library", Capacity: 40, TimeZone: "America/Los_Angeles"}, event.Details())
+
+	// Only the flags given change.
+	edited, err := (&eventFlags{venue: "", group: "Hack Night Seattle"}).apply(event, map[string]bool{"venue": true, "group": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "Hack Night", edited.Name())
+	assert.Equal(t, event.Time(), edited.Time())
+	assert.Equal(t, models.EventDetails{Group: "Hack Night Seattle", Capacity: 40, TimeZone: "America/Los_Angeles"}, edited.Details())
+
+	_, err = (&eventFlags{timeZone: "Pacific"}).apply(event, map[string]bool{"time-zone": true})
+	assert.Error(t, err)
+	_, err = (&eventFlags{capacity: -1}).apply(event, map[string]bool{"capacity": true})
+	assert.Error(t, err)
+	_, err = (&eventFlags{time: "March 14"}).apply(event, map[string]bool{"time": true})
+	assert.Error(t, err)
+}
+
+func TestEventFlagsApplyDefaultTimeZone(t *testing.T) {
+	defer func(location *time.Location) { defaultLocation = location }(defaultLocation)
+	var err error
+	defaultLocation, err = time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	// Times are read in --default-time-zone when the event doesn't name one,
+	// on either side of the clocks changing.
+	event, err := (&eventFlags{time: "2019-11-02 18:30"}).apply(models.NewEvent("", "event-1", nil), map[string]bool{"time": true})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.November, 2, 22, 30, 0, 0, time.UTC), event.Time().UTC())
+	event, err = (&eventFlags{time: "2019-11-03 18:30"}).apply(event, map[string]bool{"time": true})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.November, 3, 23, 30, 0, 0, time.UTC), event.Time().UTC())
+
+	_, err = (&eventFlags{time: "2019-03-10 02:30"}).apply(event, map[string]bool{"time": true})
+	assert.EqualError(t, err, "error parsing --time: March 10, 2019 2:30 AM doesn't happen in America/New_York: clocks skip it for daylight saving time")
+	_, err = (&eventFlags{time: "11/3/2019 1:30am"}).apply(event, map[string]bool{"time": true})
+	assert.EqualError(t, err, "error parsing --time: November 3, 2019 1:30 AM happens twice in America/New_York as clocks turn back; give its UTC offset, as in 2019-11-03T01:30:00-04:00")
+	event, err = (&eventFlags{time: "2019-11-03T01:30:00-05:00"}).apply(event, map[string]bool{"time": true})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.November, 3, 6, 30, 0, 0, time.UTC), event.Time().UTC())
+	_, err = (&eventFlags{time: "2019-03-14"}).apply(event, map[string]bool{"time": true})
+	assert.EqualError(t, err, `--time needs a time of day, as in "2019-03-14 6:30pm"`)
+
+	// Changing the time zone alone keeps the event's clock time.
+	event, err = (&eventFlags{time: "2019-11-02 18:30"}).apply(event, map[string]bool{"time": true})
+	assert.NoError(t, err)
+	moved, err := (&eventFlags{timeZone: "America/Los_Angeles"}).apply(event, map[string]bool{"time-zone": true})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.November, 3, 1, 30, 0, 0, time.UTC), moved.Time().UTC())
+	assert.Equal(t, "America/Los_Angeles", moved.Details().TimeZone)
+}
+
+func TestEventDetailsTable(t *testing.T) {
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime).WithDetails(models.EventDetails{Duration: 2 * time.Hour})
+
+	table := eventDetailsTable(event, "json")
+	assert.Len(t, table.Rows, 1)
+	assert.Equal(t, "2h0m0s", table.Rows[0][4])
+	assert.Nil(t, table.Rows[0][7])
+
+	table = eventDetailsTable(event, "table")
+	assert.Len(t, table.Rows, len(eventDetailColumns))
+	assert.Equal(t, []interface{}{"Time", "2019-03-14 06:30 PM UTC"}, table.Rows[2])
+}
+
+func TestEventImportsTable(t *testing.T) {
+	importedAt := time.Date(2019, time.March, 15, 9, 30, 0, 0, time.UTC)
+	table := eventImportsTable([]*models.Import{models.NewImport("event-1", "rsvps.tsv", "abc123", importedAt, 12)}, time.UTC)
+	assert.Equal(t, [][]interface{}{{importedAt, "rsvps.tsv", 12, "abc123"}}, table.Rows)
+	assert.Empty(t, eventImportsTable(nil, time.UTC).Rows)
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/cli/export"
+)
+
+type exportGuestListCommand struct {
+	dbFileName      string
+	eventID         string
+	format          string
+	output          string
+	followUpOutput  string
+	includeDeclined bool
+}
+
+// createOutput opens the named file for writing, or stdout for "" and "-".
+func createOutput(name string) (io.WriteCloser, error) {
+	if name == "" || name == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating output file %#v", name)
+	}
+	return file, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// followUpFileName derives "guests-followup.csv" from "guests.csv".
+func followUpFileName(output string) string {
+	ext := filepath.Ext(output)
+	return strings.TrimSuffix(output, ext) + "-followup" + ext
+}
+
+func (e *exportGuestListCommand) run(c *kingpin.ParseContext) error {
+	if e.format == "xlsx" && (e.output == "" || e.output == "-") {
+		return errors.New("--output is required for xlsx")
+	}
+
+	store, err := openStore(e.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	event, err := store.FetchEvent(e.eventID)
+	if err != nil {
+		return errors.Wrap(err, "error getting event from storage")
+	}
+	attendances, err := store.GetAttendancesForEvent(e.eventID)
+	if err != nil {
+		return errors.Wrap(err, "error getting attendances from storage")
+	}
+	guestList := export.NewGuestList(event.Local(defaultLocation), attendances, e.includeDeclined)
+
+	out, err := createOutput(e.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	switch e.format {
+	case "xlsx":
+		err = guestList.WriteXLSX(out)
+	case "csv":
+		err = guestList.WriteCSV(out)
+	default:
+		err = guestList.WriteText(out)
+	}
+	if err != nil {
+		return errors.Wrap(err, "error writing guest list")
+	}
+
+	// The xlsx and text layouts include the follow-up list; CSV can only
+	// hold one table, so it goes to its own file.
+	if e.format == "csv" && len(guestList.NeedsFollowUp) > 0 {
+		followUpOutput := e.followUpOutput
+		if followUpOutput == "" && e.output != "" && e.output != "-" {
+			followUpOutput = followUpFileName(e.output)
+		}
+		if followUpOutput == "" {
+			fmt.Fprintf(os.Stderr, "%d guests need follow-up for a legal name, use --follow-up-output to save them\n", len(guestList.NeedsFollowUp))
+			return nil
+		}
+		followUp, err := createOutput(followUpOutput)
+		if err != nil {
+			return err
+		}
+		defer followUp.Close()
+		if err := guestList.WriteFollowUpCSV(followUp); err != nil {
+			return errors.Wrap(err, "error writing follow-up list")
+		}
+		fmt.Fprintf(os.Stderr, "%d guests need follow-up, saved to %#v\n", len(guestList.NeedsFollowUp), followUpOutput)
+	}
+	return nil
+}
+
+func AddExportSubcommand(app *kingpin.Application) {
+	c := app.Command("export", "export information from storage for use elsewhere")
+
+	gc := &exportGuestListCommand{}
+	g := c.Command("guestlist", "export the legal-name guest list for building security").Action(gc.run)
+	g.Arg("event-id", "the ID of the event").Required().StringVar(&gc.eventID)
+	addStoreFlag(g, &gc.dbFileName)
+	g.Flag("format", "the output format").Short('f').Default("text").EnumVar(&gc.format, "text", "csv", "xlsx")
+	g.Flag("output", "the file to write to, stdout by default").Short('o').StringVar(&gc.output)
+	g.Flag("follow-up-output", "the CSV file for guests without a legal name").StringVar(&gc.followUpOutput)
+	g.Flag("include-declined", "also list people who RSVPed no").BoolVar(&gc.includeDeclined)
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/forecast"
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+type forecastCommand struct {
+	dbFileName string
+	eventID    string
+	level      float64
+	output     string
+	guests     bool
+	model      string
+}
+
+// allAttendances loads every stored attendance, event by event.
+func allAttendances(store storage.Store) ([]*models.Attendance, error) {
+	events, err := store.GetAllEvents()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting events from storage")
+	}
+	var attendances []*models.Attendance
+	for _, event := range events {
+		forEvent, err := store.GetAttendancesForEvent(event.ID())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting attendances for event %#v", event.ID())
+		}
+		attendances = append(attendances, forEvent...)
+	}
+	return attendances, nil
+}
+
+func writeForecastTable(w io.Writer, f *forecast.Forecast, guests bool) error {
+	fmt.Fprintf(w, "%d RSVPs, expecting %.1f (%d to %d, %.0f%% interval)\n",
+		f.RSVPs, f.Expected, f.Low, f.High, f.Level*100)
+	if !guests {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nUser ID\tName\tChance")
+	for _, guest := range f.Guests {
+		fmt.Fprintf(tw, "%s\t%s\t%.0f%%\n", guest.UserID, guest.PreferredName, guest.Probability*100)
+	}
+	return tw.Flush()
+}
+
+func (f *forecastCommand) run(c *kingpin.ParseContext) error {
+	store, err := openStore(f.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	event, err := store.FetchEvent(f.eventID)
+	if err != nil {
+		return errors.Wrap(err, "error getting event from storage")
+	}
+	if event.Time() == nil {
+		return errors.Errorf("event %#v has no time to forecast from", f.eventID)
+	}
+	rsvps, err := store.GetAttendancesForEvent(f.eventID)
+	if err != nil {
+		return errors.Wrap(err, "error getting attendances from storage")
+	}
+	attendances, err := allAttendances(store)
+	if err != nil {
+		return err
+	}
+
+	model, err := forecast.ModelByName(f.model)
+	if err != nil {
+		return err
+	}
+
+	history := forecast.NewHistory(attendances, *event.Time())
+	prediction, err := forecast.Predict(event, rsvps, history, model, f.level)
+	if err != nil {
+		return err
+	}
+	if f.output == "json" {
+		return writeJSON(os.Stdout, prediction)
+	}
+	return writeForecastTable(os.Stdout, prediction, f.guests)
+}
+
+func writeBacktestTable(w io.Writer, report *forecast.BacktestReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Event\tTime\tRSVPs\tForecast\tInterval\tActual\tError")
+	for _, e := range report.Events {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\t%d-%d\t%d\t%+.1f\n",
+			e.EventName, e.EventTime.In(defaultLocation).Format(reportDateDisplayFormat),
+			e.Forecast.RSVPs, e.Forecast.Expected, e.Forecast.Low, e.Forecast.High,
+			e.Actual, e.Error())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nmodel %s over %d events: MAE %.2f, MAPE %.1f%%, bias %+.2f, %.0f%% of actuals inside the %.0f%% interval\n",
+		report.Model, len(report.Events), report.MAE, report.MAPE*100, report.Bias,
+		report.Coverage*100, report.Level*100)
+	if report.Skipped > 0 {
+		fmt.Fprintf(w, "skipped %d past events without recorded check-ins\n", report.Skipped)
+	}
+	return nil
+}
+
+func (f *forecastCommand) backtest(c *kingpin.ParseContext) error {
+	model, err := forecast.ModelByName(f.model)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(f.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	events, err := store.GetAllEvents()
+	if err != nil {
+		return errors.Wrap(err, "error getting events from storage")
+	}
+	attendances, err := allAttendances(store)
+	if err != nil {
+		return err
+	}
+	report, err := forecast.Backtest(events, attendances, model, f.level, time.Now())
+	if err != nil {
+		return err
+	}
+	if f.output == "json" {
+		return writeJSON(os.Stdout, report)
+	}
+	return writeBacktestTable(os.Stdout, report)
+}
+
+func addForecastFlags(c *kingpin.CmdClause, fc *forecastCommand) {
+	addStoreFlag(c, &fc.dbFileName)
+	c.Flag("model", "the forecasting model").Default(forecast.DefaultModel().Name()).EnumVar(&fc.model, forecast.ModelNames()...)
+	c.Flag("level", "the coverage of the forecast interval").Default(fmt.Sprint(forecast.DefaultLevel)).Float64Var(&fc.level)
+	c.Flag("output", "the output format").Short('o').Default("table").EnumVar(&fc.output, "table", "json")
+}
+
+func AddForecastSubcommand(app *kingpin.Application) {
+	c := app.Command("forecast", "forecast turnout for events")
+
+	fc := &forecastCommand{}
+	e := c.Command("event", "forecast turnout for an event from its RSVPs").Default().Action(fc.run)
+	e.Arg("event-id", "the ID of the event").Required().StringVar(&fc.eventID)
+	addForecastFlags(e, fc)
+	e.Flag("guests", "also show each RSVP's chance of showing up").BoolVar(&fc.guests)
+
+	b := c.Command("backtest", "measure how well a model would have forecast past events").Action(fc.backtest)
+	addForecastFlags(b, fc)
+}
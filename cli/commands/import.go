@@ -1,78 +1,121 @@
 package commands
 
 import (
-	"database/sql"
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/alexthemitchell/community-attendance/cli/reader"
+	"github.com/alexthemitchell/community-attendance/importer"
 	"github.com/alexthemitchell/community-attendance/models"
-	"github.com/alexthemitchell/community-attendance/storage/sql"
 )
 
 var log = logrus.StandardLogger()
 
 type importCommand struct {
-	fileName   string
-	eventName  string
-	eventTime  string
-	dbFileName string
+	fileName          string
+	eventName         string
+	eventTime         string
+	eventID           string
+	sourceID          string
+	timeZone          string
+	dbFileName        string
+	legalNameQuestion string
+	format            string
+	sheet             string
 }
 
-func persistImport(dbName string, event *models.Event, records []*models.Attendance) error {
-	db, err := sql.Open("sqlite3", dbName)
+// dryRunDSN is the storage used when an import isn't saved, which has no
+// events for it to match.
+const dryRunDSN = "memory://"
+
+func (i *importCommand) run(c *kingpin.ParseContext) error {
+	// Relative times such as "tomorrow 6pm" count from today where the
+	// event is, if that's known yet.
+	referenceLocation := defaultLocation
+	if i.timeZone != "" {
+		location, err := loadLocation(i.timeZone)
+		if err != nil {
+			return err
+		}
+		referenceLocation = location
+	}
+	eventTime, abbreviation, err := parseImportTime(i.eventTime, time.Now().In(referenceLocation))
 	if err != nil {
-		return errors.Wrapf(err, "error opening DB file: %#v", dbName)
+		return err
 	}
-	storage, err := storage.NewSQLStorage(db)
+
+	dsn := i.dbFileName
+	if dsn == "" {
+		dsn = dryRunDSN
+	}
+	store, err := openStore(dsn)
 	if err != nil {
-		return errors.Wrapf(err, "error initializing SQL storage")
+		return err
 	}
-	defer storage.Close()
-	err = storage.CreateAttendeesTable()
+	defer store.Close()
+	event, err := importer.ResolveEvent(store, importer.EventKey{
+		ID:       i.eventID,
+		SourceID: i.sourceID,
+		Name:     i.eventName,
+		Time:     eventTime.Wall(),
+		TimeZone: i.timeZone,
+	}, defaultLocation)
 	if err != nil {
-		return errors.Wrap(err, "error creating attendees table")
+		return errors.Wrap(err, "error finding event to import into")
 	}
-	err = storage.UpsertEvent(event)
+	location := event.Location(defaultLocation)
+	// The time was already read as a wall clock in the event's time zone;
+	// this catches ones daylight saving makes ambiguous, and uses the
+	// instant itself if a UTC offset was given.
+	exact, err := eventTime.In(location)
 	if err != nil {
-		return errors.Wrap(err, "error upserting event")
+		return errors.Wrap(err, "error parsing event time")
 	}
-	for _, record := range records {
-		err = storage.UpsertAttendee(record.Attendee())
-		if err != nil {
-			log.WithField("attendee", record.Attendee()).WithError(err).Error("error upserting attendee")
-			return errors.Wrap(err, "error upserting attendee")
-		}
+	if !exact.Equal(*event.Time()) {
+		event = models.NewEvent(event.Name(), event.ID(), &exact).WithDetails(event.Details())
 	}
-	return nil
-}
-
-func (i *importCommand) run(c *kingpin.ParseContext) error {
-	eventTime, err := time.Parse("January _2, 2006 3:04PM PST", i.eventTime)
-	if err != nil {
-		return errors.Wrap(err, "unable to parse time")
+	if zone, _ := event.Time().In(location).Zone(); abbreviation != "" && abbreviation != zone {
+		log.Warnf("the event time says %s, but the event is at %s in %s; use --time-zone if that's wrong",
+			abbreviation, event.Time().In(location).Format("3:04PM MST"), location)
 	}
 
-	uuid, err := uuid.NewRandom()
-	if err != nil {
-		return errors.Wrap(err, "unable to create random UUID for event")
+	options := reader.DefaultOptions()
+	options.Columns.LegalNameQuestion = i.legalNameQuestion
+	options.Sheet = i.sheet
+	options.Location = location
+	format := i.format
+	if format == "auto" {
+		format = ""
 	}
-
-	event := models.NewEvent(i.eventName, uuid.String(), &eventTime)
-	attendance, errs := reader.ParseAttendanceFromFile(i.fileName, event)
+	attendance, errs := reader.ParseFile(i.fileName, format, event, options)
 	if len(errs) > 0 {
 		return errors.Wrap(errs[0], "error reading from file")
 	}
 
 	fmt.Printf("processed %d attendance records\n", len(attendance))
 	if i.dbFileName != "" {
-		persistImport(i.dbFileName, event, attendance)
-		fmt.Printf("saved to SQLiteDB %#v\n", i.dbFileName)
+		source, err := importer.SourceFromFile(i.fileName, "")
+		if err != nil {
+			return err
+		}
+		summary, err := importer.Persist(store, event, attendance, source)
+		if err != nil {
+			return errors.Wrap(err, "error saving import")
+		}
+		fmt.Printf("saved to %#v\n", i.dbFileName)
+		fmt.Printf("event: %s (%s)\n", summary.Event, event.ID())
+		fmt.Printf("attendees: %s\n", summary.Attendees)
+		fmt.Printf("attendances: %s\n", summary.Attendances)
+		if summary.Merged > 0 {
+			fmt.Printf("records for merged attendees: %d\n", summary.Merged)
+		}
+		if summary.Cancelled > 0 {
+			fmt.Printf("cancelled RSVPs missing from this export: %d\n", summary.Cancelled)
+		}
 	}
 	return nil
 }
@@ -81,8 +124,14 @@ func AddImportSubcommand(app *kingpin.Application) {
 	c := app.Command("import", "import attendance information from another source")
 	ic := &importCommand{}
 	f := c.Command("file", "import information from a local file").Action(ic.run)
-	f.Arg("event-name", "the name of the event").Required().StringVar(&ic.fileName)
-	f.Arg("event-time", "the time and date of the event").Required().StringVar(&ic.eventTime)
+	f.Arg("event-name", "the name of the event").Required().StringVar(&ic.eventName)
+	f.Arg("event-time", "when the event starts in its time zone, such as \"March 14, 2019 6:30PM\", \"2019-03-14 18:30\" or \"last Tuesday 6:30pm\"").Required().StringVar(&ic.eventTime)
 	f.Arg("file-name", "the name of the file to read").Required().StringVar(&ic.fileName)
-	f.Flag("local", "save the data in a local sqlite db file with the provided name").Short('l').StringVar(&ic.dbFileName)
+	f.Flag("event-id", "the ID of the event to import into, created if it doesn't exist").StringVar(&ic.eventID)
+	f.Flag("source-id", "the event's ID in the system the file was exported from, used to find it on later imports").StringVar(&ic.sourceID)
+	f.Flag("time-zone", "the IANA time zone of the event, such as America/Los_Angeles; by default the event's own or --default-time-zone").StringVar(&ic.timeZone)
+	f.Flag("local", "save the data to the given storage DSN or local sqlite db file").Short('l').StringVar(&ic.dbFileName)
+	f.Flag("legal-name-question", "text that identifies the RSVP question asking for the attendee's legal name").Default(reader.DefaultMeetupColumns.LegalNameQuestion).StringVar(&ic.legalNameQuestion)
+	f.Flag("format", "the format of the file, detected from its name and contents by default").Default("auto").EnumVar(&ic.format, append([]string{"auto"}, reader.FormatNames()...)...)
+	f.Flag("sheet", "the name or 1-based position of the worksheet to read from a workbook").StringVar(&ic.sheet)
 }
@@ -1,14 +1,67 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/cli/output"
+	"github.com/alexthemitchell/community-attendance/dateparse"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
 )
 
-func max(a, b int) int {
-	if a < b {
-		return b
+// pageFlags are the sorting and paging flags shared by list commands.
+type pageFlags struct {
+	sort   string
+	limit  int
+	offset int
+}
+
+func addPageFlags(c *kingpin.CmdClause, flags *pageFlags, sortKeys []string) {
+	c.Flag("sort", fmt.Sprintf("sort by %s; prefix with - to reverse, as in --sort=-%s", strings.Join(sortKeys, ", "), sortKeys[0])).Default(sortKeys[0]).StringVar(&flags.sort)
+	c.Flag("limit", "show at most this many rows").IntVar(&flags.limit)
+	c.Flag("offset", "skip this many rows first").IntVar(&flags.offset)
+}
+
+// parseDateFlag parses the value of a date flag, returning nil if it's
+// empty. Times are read in location, and dates alone mean the start of the
+// day there. Relative dates such as "last Tuesday" count from today in
+// --default-time-zone.
+func parseDateFlag(flag, value string, location *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := dateparse.Parse(value, time.Now().In(defaultLocation))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing --%s", flag)
+	}
+	t, err := date.In(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing --%s", flag)
+	}
+	return &t, nil
+}
+
+// parseTimeFlag parses the value of a flag that needs a time of day, such
+// as "2019-03-14 18:30" or "next Tuesday 6:30pm", in location.
+func parseTimeFlag(flag, value string, location *time.Location) (time.Time, error) {
+	date, err := dateparse.Parse(value, time.Now().In(location))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "error parsing --%s", flag)
 	}
-	return a
+	if !date.HasClock() {
+		return time.Time{}, errors.Errorf("--%s needs a time of day, as in \"%s 6:30pm\"", flag, value)
+	}
+	t, err := date.In(location)
+	return t, errors.Wrapf(err, "error parsing --%s", flag)
+}
+
+// addOutputFlag adds the --output flag choosing how results are rendered.
+func addOutputFlag(c *kingpin.CmdClause, format *string) {
+	c.Flag("output", "the output format").Short('o').Default(output.Formats()[0]).EnumVar(format, output.Formats()...)
 }
 
 func AddListSubcommand(app *kingpin.Application) {
@@ -16,9 +69,20 @@ func AddListSubcommand(app *kingpin.Application) {
 
 	lac := &listAttendeesCommand{}
 	a := c.Command("attendees", "show list of attendees").Action(lac.run)
-	a.Arg("db-file-name", "the name of the sqlite db file").Required().StringVar(&lac.dbFileName)
+	a.Arg("db-file-name", "the storage DSN or name of the sqlite db file").Required().StringVar(&lac.dbFileName)
+	a.Flag("host", "only list hosts (yes) or non-hosts (no)").EnumVar(&lac.host, "yes", "no")
+	a.Flag("joined-from", "only list attendees who joined on or after this date, such as 2019-03-14 or \"2 weeks ago\"").StringVar(&lac.joinedFrom)
+	a.Flag("joined-to", "only list attendees who joined before this date").StringVar(&lac.joinedTo)
+	a.Flag("attended", "only list attendees who attended the event with this ID").StringVar(&lac.attended)
+	addPageFlags(a, &lac.page, storage.AttendeeSortKeys())
+	addOutputFlag(a, &lac.output)
 
 	lec := &listEventsCommand{}
 	e := c.Command("events", "show list of events").Action(lec.run)
-	e.Arg("db-file-name", "the name of the sqlite db file").Required().StringVar(&lec.dbFileName)
+	e.Arg("db-file-name", "the storage DSN or name of the sqlite db file").Required().StringVar(&lec.dbFileName)
+	e.Flag("from", "only list events on or after this date or time, such as 2019-03-14 or \"last Tuesday\"").StringVar(&lec.from)
+	e.Flag("to", "only list events before this date or time").StringVar(&lec.to)
+	e.Flag("name", "only list events whose name contains this, ignoring case").StringVar(&lec.name)
+	addPageFlags(e, &lec.page, storage.EventSortKeys())
+	addOutputFlag(e, &lec.output)
 }
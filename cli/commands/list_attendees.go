@@ -1,79 +1,99 @@
 package commands
 
 import (
-	"database/sql"
-	"fmt"
 	"os"
+	"time"
 
-	"github.com/logrusorgru/aurora"
 	"github.com/pkg/errors"
 	"gopkg.in/alecthomas/kingpin.v2"
 
+	"github.com/alexthemitchell/community-attendance/cli/output"
 	"github.com/alexthemitchell/community-attendance/models"
-	"github.com/alexthemitchell/community-attendance/storage/sql"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
 )
 
 const joinDateDisplayFormat = "2006-01-02"
 
 type listAttendeesCommand struct {
 	dbFileName string
+	host       string
+	joinedFrom string
+	joinedTo   string
+	attended   string
+	page       pageFlags
+	output     string
 }
 
-func attendeeLineFormatWithMaxLengths(maxPreferredName, maxLegalName, maxJoinedDate int) string {
-	return fmt.Sprintf("%%-%ds\t%%%ds\t%%%ds\t%%s\n", maxPreferredName, maxLegalName, maxJoinedDate)
-}
-
-func lineFormatForAttendees(attendees []*models.Attendee) string {
-	var maxPreferredNameLength int
-	var maxLegalNameLength int
-	var maxJoinedDateLength int
-	for _, attendee := range attendees {
-		maxPreferredNameLength = max(maxPreferredNameLength, len(attendee.PreferredName()))
-		maxLegalNameLength = max(maxLegalNameLength, len(attendee.LegalName()))
-		maxJoinedDateLength = max(maxJoinedDateLength, len(attendee.JoinedDate().Format(joinDateDisplayFormat)))
+// query builds the storage query selected by the command's flags.
+func (l *listAttendeesCommand) query() (storage.AttendeeQuery, error) {
+	query := storage.AttendeeQuery{
+		AttendedEventID: l.attended,
+		Sort:            l.page.sort,
+		Limit:           l.page.limit,
+		Offset:          l.page.offset,
+	}
+	if l.host != "" {
+		isHost := l.host == "yes"
+		query.IsHost = &isHost
+	}
+	var err error
+	if query.JoinedFrom, err = parseDateFlag("joined-from", l.joinedFrom, time.UTC); err != nil {
+		return query, err
 	}
+	if query.JoinedTo, err = parseDateFlag("joined-to", l.joinedTo, time.UTC); err != nil {
+		return query, err
+	}
+	return query, query.Validate()
+}
 
-	return attendeeLineFormatWithMaxLengths(
-		maxPreferredNameLength,
-		maxLegalNameLength,
-		maxJoinedDateLength)
+var attendeeColumns = []output.Column{
+	{Key: "user_id", Header: "User ID"},
+	{Key: "preferred_name", Header: "Preferred Name"},
+	{Key: "legal_name", Header: "Legal Name"},
+	{Key: "email", Header: "Email"},
+	{Key: "joined_date", Header: "Joined Date", Layout: joinDateDisplayFormat},
+	{Key: "is_host", Header: "Host?"},
+	{Key: "profile_url", Header: "Profile URL"},
 }
 
-func printAttendeesToScreen(attendees []*models.Attendee) {
-	lineFormat := lineFormatForAttendees(attendees)
-	fmt.Fprintf(os.Stdout, lineFormat,
-		aurora.Bold("Preferred Name"),
-		aurora.Bold("Legal Name"),
-		aurora.Bold("Joined Date"),
-		aurora.Bold("Host?"),
-	)
+func attendeesTable(attendees []*models.Attendee) *output.Table {
+	t := &output.Table{Columns: attendeeColumns}
 	for _, attendee := range attendees {
-		var hostMarker string
-		if attendee.IsHost() {
-			hostMarker = "Host"
+		var profileURL string
+		if attendee.ProfileURL() != nil {
+			profileURL = attendee.ProfileURL().String()
 		}
-		fmt.Fprintf(os.Stdout, lineFormat,
+		t.Rows = append(t.Rows, []interface{}{
+			attendee.UserID(),
 			attendee.PreferredName(),
 			attendee.LegalName(),
-			attendee.JoinedDate().Format(joinDateDisplayFormat),
-			hostMarker)
+			attendee.Email(),
+			attendee.JoinedDate(),
+			attendee.IsHost(),
+			profileURL,
+		})
 	}
+	return t
+}
+
+// writeOutput renders t to stdout, in color only when stdout is a terminal.
+func writeOutput(format string, t *output.Table) error {
+	return output.Write(os.Stdout, format, t, output.IsTerminal(os.Stdout))
 }
 
 func (l *listAttendeesCommand) run(c *kingpin.ParseContext) error {
-	db, err := sql.Open("sqlite3", l.dbFileName)
+	query, err := l.query()
 	if err != nil {
-		return errors.Wrapf(err, "error opening DB file%#v", l.dbFileName)
+		return err
 	}
-	storage, err := storage.NewSQLStorage(db)
+	store, err := openStore(l.dbFileName)
 	if err != nil {
-		return errors.Wrapf(err, "error initializing SQL storage")
+		return err
 	}
-	defer storage.Close()
-	attendees, err := storage.GetAllAttendees()
+	defer store.Close()
+	attendees, err := store.QueryAttendees(query)
 	if err != nil {
 		return errors.Wrap(err, "error getting attendees from storage")
 	}
-	printAttendeesToScreen(attendees)
-	return nil
+	return writeOutput(l.output, attendeesTable(attendees))
 }
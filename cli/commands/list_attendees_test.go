@@ -1 +1,29 @@
 package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAttendeesQuery(t *testing.T) {
+	l := &listAttendeesCommand{host: "no", joinedFrom: "2019-01-01", attended: "event-1", page: pageFlags{sort: "-joined", limit: 5}}
+	query, err := l.query()
+	assert.NoError(t, err)
+	assert.False(t, *query.IsHost)
+	assert.Equal(t, time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC), *query.JoinedFrom)
+	assert.Nil(t, query.JoinedTo)
+	assert.Equal(t, "event-1", query.AttendedEventID)
+	assert.Equal(t, "-joined", query.Sort)
+	assert.Equal(t, 5, query.Limit)
+
+	query, err = (&listAttendeesCommand{}).query()
+	assert.NoError(t, err)
+	assert.Nil(t, query.IsHost)
+
+	_, err = (&listAttendeesCommand{joinedTo: "January 2019"}).query()
+	assert.Error(t, err)
+	_, err = (&listAttendeesCommand{page: pageFlags{sort: "time"}}).query()
+	assert.Error(t, err)
+}
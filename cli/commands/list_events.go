@@ -1,73 +1,75 @@
 package commands
 
 import (
-	"database/sql"
-	"fmt"
-	"os"
+	"time"
 
-	"github.com/logrusorgru/aurora"
 	"github.com/pkg/errors"
 	"gopkg.in/alecthomas/kingpin.v2"
 
+	"github.com/alexthemitchell/community-attendance/cli/output"
 	"github.com/alexthemitchell/community-attendance/models"
-	"github.com/alexthemitchell/community-attendance/storage/sql"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
 )
 
-const eventTimeDisplayFormat = "2006-01-02 03:04 PM PST"
+const eventTimeDisplayFormat = "2006-01-02 03:04 PM MST"
 
 type listEventsCommand struct {
 	dbFileName string
+	from       string
+	to         string
+	name       string
+	page       pageFlags
+	output     string
 }
 
-func lineFormatForEvents(events []*models.Event) string {
-	var maxNameLength int
-	var maxTimeLength int
-	var maxIDLength int
-	for _, event := range events {
-		maxNameLength = max(maxNameLength, len(event.Name()))
-		maxIDLength = max(maxIDLength, len(event.ID()))
-		maxTimeLength = max(maxTimeLength, len(event.Time().Format(eventTimeDisplayFormat)))
+// query builds the storage query selected by the command's flags.
+func (l *listEventsCommand) query() (storage.EventQuery, error) {
+	query := storage.EventQuery{
+		NameContains: l.name,
+		Sort:         l.page.sort,
+		Limit:        l.page.limit,
+		Offset:       l.page.offset,
 	}
-
-	return eventLineFormatWithMaxLengths(
-		maxNameLength,
-		maxIDLength,
-		maxTimeLength)
+	var err error
+	if query.From, err = parseDateFlag("from", l.from, defaultLocation); err != nil {
+		return query, err
+	}
+	if query.To, err = parseDateFlag("to", l.to, defaultLocation); err != nil {
+		return query, err
+	}
+	return query, query.Validate()
 }
-func eventLineFormatWithMaxLengths(maxNameLength, maxIDLength, maxTimeLength int) string {
-	return fmt.Sprintf("%%-%ds\t%%%ds\t%%%ds\n", maxNameLength, maxIDLength, maxTimeLength)
+
+var eventColumns = []output.Column{
+	{Key: "id", Header: "ID"},
+	{Key: "name", Header: "Event"},
+	{Key: "time", Header: "Time", Layout: eventTimeDisplayFormat},
 }
 
-func printEventsToScreen(events []*models.Event) {
-	lineFormat := lineFormatForEvents(events)
-	fmt.Fprintf(os.Stdout, lineFormat,
-		aurora.Bold("Event"),
-		aurora.Bold("Time"),
-		aurora.Bold("ID"),
-	)
+// eventsTable shows each event's time in its time zone, or in location if
+// it has none.
+func eventsTable(events []*models.Event, location *time.Location) *output.Table {
+	t := &output.Table{Columns: eventColumns}
 	for _, event := range events {
-		fmt.Fprintf(os.Stdout, lineFormat,
-			event.Name(),
-			event.Time().String(),
-			event.ID())
+		event = event.Local(location)
+		t.Rows = append(t.Rows, []interface{}{event.ID(), event.Name(), event.Time()})
 	}
+	return t
 }
 
 func (l *listEventsCommand) run(c *kingpin.ParseContext) error {
-	db, err := sql.Open("sqlite3", l.dbFileName)
+	query, err := l.query()
 	if err != nil {
-		return errors.Wrapf(err, "error opening DB file%#v", l.dbFileName)
+		return err
 	}
-	storage, err := storage.NewSQLStorage(db)
+	store, err := openStore(l.dbFileName)
 	if err != nil {
-		return errors.Wrapf(err, "error initializing SQL storage")
+		return err
 	}
-	defer storage.Close()
-	events, err := storage.GetAllEvents()
+	defer store.Close()
+	events, err := store.QueryEvents(query)
 	if err != nil {
 		return errors.Wrap(err, "error getting events from storage")
 	}
-	fmt.Println(len(events))
-	printEventsToScreen(events)
-	return nil
+	return writeOutput(l.output, eventsTable(events, defaultLocation))
 }
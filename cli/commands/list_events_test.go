@@ -1 +1,64 @@
 package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestEventsTableLabelsMatchValues(t *testing.T) {
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	table := eventsTable([]*models.Event{models.NewEvent("Hack Night", "event-1", &eventTime)}, time.UTC)
+	values := map[string]interface{}{}
+	for i, column := range table.Columns {
+		values[column.Header] = table.Rows[0][i]
+	}
+	assert.Equal(t, "Hack Night", values["Event"])
+	assert.Equal(t, "event-1", values["ID"])
+	assert.Equal(t, &eventTime, values["Time"])
+}
+
+func TestEventsTableTimeZones(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	// The first event is a week before clocks spring forward, the second
+	// the day after.
+	before := time.Date(2019, time.March, 3, 23, 30, 0, 0, time.UTC)
+	after := time.Date(2019, time.March, 11, 1, 30, 0, 0, time.UTC)
+	events := []*models.Event{
+		models.NewEvent("Hack Night", "event-1", &before),
+		models.NewEvent("Hack Night", "event-2", &after).WithDetails(models.EventDetails{TimeZone: "America/Los_Angeles"}),
+	}
+	table := eventsTable(events, newYork)
+	assert.Equal(t, "2019-03-03 06:30 PM EST", table.Rows[0][2].(*time.Time).Format(eventTimeDisplayFormat))
+	assert.Equal(t, "2019-03-10 06:30 PM PDT", table.Rows[1][2].(*time.Time).Format(eventTimeDisplayFormat))
+}
+
+func TestListEventsQuery(t *testing.T) {
+	defer func(location *time.Location) { defaultLocation = location }(defaultLocation)
+	defaultLocation = time.UTC
+	l := &listEventsCommand{from: "2019-03-01", to: "2019-04-01", name: "hack", page: pageFlags{sort: "name", offset: 10}}
+	query, err := l.query()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC), *query.From)
+	assert.Equal(t, time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC), *query.To)
+	assert.Equal(t, "hack", query.NameContains)
+	assert.Equal(t, 10, query.Offset)
+
+	// Dates start at midnight in --default-time-zone.
+	defaultLocation, err = time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+	query, err = (&listEventsCommand{from: "2019-03-10", to: "March 11, 2019"}).query()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 10, 8, 0, 0, 0, time.UTC), query.From.UTC())
+	assert.Equal(t, time.Date(2019, time.March, 11, 7, 0, 0, 0, time.UTC), query.To.UTC())
+
+	_, err = (&listEventsCommand{from: "14/03/2019"}).query()
+	assert.EqualError(t, err, `error parsing --from: can't read "14/03/2019" as a date: 14/03/2019 isn't month/day/year; write dates like 3/14/2019 or 2019-03-14`)
+
+	_, err = (&listEventsCommand{page: pageFlags{limit: -1}}).query()
+	assert.Error(t, err)
+}
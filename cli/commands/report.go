@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/analytics"
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+const reportDateDisplayFormat = "2006-01-02"
+
+type reportCommand struct {
+	dbFileName string
+	userID     string
+	sortKey    string
+	asOf       string
+	output     string
+}
+
+// now returns the time the report is as of: --as-of if given, otherwise
+// the current time.
+func (r *reportCommand) now() (time.Time, error) {
+	if r.asOf == "" {
+		return time.Now(), nil
+	}
+	asOf, err := parseDateFlag("as-of", r.asOf, defaultLocation)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *asOf, nil
+}
+
+func attendeeStats(store storage.Store, userID string, now time.Time) (*analytics.AttendeeStats, error) {
+	attendee, err := store.FetchAttendee(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting attendee from storage")
+	}
+	attendances, err := store.GetAttendancesForAttendee(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting attendances from storage")
+	}
+	// Everyone's attendances at the attendee's events say which ones
+	// check-ins were taken at.
+	var atTheirEvents []*models.Attendance
+	for _, attendance := range attendances {
+		forEvent, err := store.GetAttendancesForEvent(attendance.Event().ID())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting attendances for event %#v", attendance.Event().ID())
+		}
+		atTheirEvents = append(atTheirEvents, forEvent...)
+	}
+	return analytics.ComputeAttendeeStats(attendee, attendances, analytics.CheckInsTaken(atTheirEvents), now), nil
+}
+
+func dateForReport(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.In(defaultLocation).Format(reportDateDisplayFormat)
+}
+
+func daysForReport(days *int) string {
+	if days == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *days)
+}
+
+func writeStatsTable(w io.Writer, stats []*analytics.AttendeeStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "User ID\tName\tRSVPed\tAttended\tWalk-ins\tNo-shows\tNo-show Rate\tStreak\tLongest\tFirst Seen\tLast Seen\tDays Joined")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%.0f%%\t%d\t%d\t%s\t%s\t%s\n",
+			s.UserID, s.PreferredName,
+			s.EventsRSVPed, s.EventsAttended, s.WalkIns, s.NoShows, s.NoShowRate*100,
+			s.CurrentStreak, s.LongestStreak,
+			dateForReport(s.FirstSeen), dateForReport(s.LastSeen),
+			daysForReport(s.DaysSinceJoined))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func writeReport(w io.Writer, output string, v interface{}, stats []*analytics.AttendeeStats) error {
+	if output == "json" {
+		return writeJSON(w, v)
+	}
+	return writeStatsTable(w, stats)
+}
+
+func (r *reportCommand) attendee(c *kingpin.ParseContext) error {
+	now, err := r.now()
+	if err != nil {
+		return err
+	}
+	store, err := openStore(r.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	stats, err := attendeeStats(store, r.userID, now)
+	if err != nil {
+		return err
+	}
+	return writeReport(os.Stdout, r.output, stats, []*analytics.AttendeeStats{stats})
+}
+
+func (r *reportCommand) attendees(c *kingpin.ParseContext) error {
+	now, err := r.now()
+	if err != nil {
+		return err
+	}
+	store, err := openStore(r.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	attendees, err := store.GetAllAttendees()
+	if err != nil {
+		return errors.Wrap(err, "error getting attendees from storage")
+	}
+	attendances, err := allAttendances(store)
+	if err != nil {
+		return err
+	}
+	checkInsTaken := analytics.CheckInsTaken(attendances)
+	byUserID := map[string][]*models.Attendance{}
+	for _, attendance := range attendances {
+		userID := attendance.Attendee().UserID()
+		byUserID[userID] = append(byUserID[userID], attendance)
+	}
+	stats := make([]*analytics.AttendeeStats, 0, len(attendees))
+	for _, attendee := range attendees {
+		stats = append(stats, analytics.ComputeAttendeeStats(attendee, byUserID[attendee.UserID()], checkInsTaken, now))
+	}
+	if err := analytics.SortStats(stats, r.sortKey); err != nil {
+		return err
+	}
+	return writeReport(os.Stdout, r.output, stats, stats)
+}
+
+func addAsOfFlag(c *kingpin.CmdClause, asOf *string) {
+	c.Flag("as-of", "report as of this date or time instead of now, such as 2019-03-14 or \"last Tuesday\"; a date alone means the start of that day").StringVar(asOf)
+}
+
+func AddReportSubcommand(app *kingpin.Application) {
+	c := app.Command("report", "report on attendance history")
+
+	rc := &reportCommand{}
+	a := c.Command("attendee", "show attendance history for one attendee").Action(rc.attendee)
+	a.Arg("user-id", "the user ID of the attendee").Required().StringVar(&rc.userID)
+	addStoreFlag(a, &rc.dbFileName)
+	addAsOfFlag(a, &rc.asOf)
+	a.Flag("output", "the output format").Short('o').Default("table").EnumVar(&rc.output, "table", "json")
+
+	all := c.Command("attendees", "show attendance history for every attendee").Action(rc.attendees)
+	addStoreFlag(all, &rc.dbFileName)
+	addAsOfFlag(all, &rc.asOf)
+	all.Flag("sort", "the statistic to sort by").Default("name").EnumVar(&rc.sortKey, analytics.SortKeys()...)
+	all.Flag("output", "the output format").Short('o').Default("table").EnumVar(&rc.output, "table", "json")
+}
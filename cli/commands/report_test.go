@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportAsOf(t *testing.T) {
+	defer func(location *time.Location) { defaultLocation = location }(defaultLocation)
+	var err error
+	defaultLocation, err = time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+
+	now, err := (&reportCommand{asOf: "3/10/2019"}).now()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 10, 8, 0, 0, 0, time.UTC), now.UTC())
+	now, err = (&reportCommand{asOf: "March 11, 2019 6:30pm"}).now()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 12, 1, 30, 0, 0, time.UTC), now.UTC())
+
+	before := time.Now()
+	now, err = (&reportCommand{}).now()
+	assert.NoError(t, err)
+	assert.False(t, now.Before(before))
+
+	_, err = (&reportCommand{asOf: "10/3"}).now()
+	assert.EqualError(t, err, `error parsing --as-of: can't read "10/3" as a date: give the year, as in 10/3/2019`)
+}
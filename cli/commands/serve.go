@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/server"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish on Ctrl-C.
+const shutdownTimeout = 5 * time.Second
+
+type serveCommand struct {
+	dbFileName string
+	addr       string
+}
+
+func (s *serveCommand) run(c *kingpin.ParseContext) error {
+	store, err := openStore(s.dbFileName)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	httpServer := &http.Server{Addr: s.addr, Handler: server.New(store, defaultLocation)}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	go func() {
+		<-interrupted
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	log.WithField("addr", s.addr).Info("serving the attendance API at /api/ and the check-in kiosk at /kiosk/")
+	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		return errors.Wrap(err, "error serving")
+	}
+	return nil
+}
+
+func AddServeSubcommand(app *kingpin.Application) {
+	sc := &serveCommand{}
+	c := app.Command("serve", "serve a JSON REST API and check-in kiosk over the storage").Action(sc.run)
+	addStoreFlag(c, &sc.dbFileName)
+	c.Flag("addr", "the address to listen on").Default(":8080").StringVar(&sc.addr)
+}
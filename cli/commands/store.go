@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+// addStoreFlag adds the --db flag naming the storage a command works on.
+func addStoreFlag(c *kingpin.CmdClause, dsn *string) {
+	c.Flag("db", "the storage DSN or name of the sqlite db file").Envar("ATTENDANCE_DB").Required().StringVar(dsn)
+}
+
+// legacyTimesChecker is storage that may hold times saved before they were
+// stored in UTC.
+type legacyTimesChecker interface {
+	HasLegacyTimes() (bool, error)
+}
+
+// openStore opens and migrates the storage a command works on. Storage
+// still holding times saved before they were stored in UTC is refused until
+// db localize-times converts them: times written alongside them couldn't be
+// told apart, and would be shifted by the conversion too.
+func openStore(dsn string) (storage.Store, error) {
+	store, err := openStoreWithLegacyTimes(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if checker, ok := store.(legacyTimesChecker); ok {
+		pending, err := checker.HasLegacyTimes()
+		if err == nil && pending {
+			err = errors.Errorf("%#v holds times saved before they were stored in UTC; run db localize-times first", dsn)
+		}
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// openStoreWithLegacyTimes opens and migrates the storage, whether or not
+// its times need converting.
+func openStoreWithLegacyTimes(dsn string) (storage.Store, error) {
+	store, err := storage.Open(dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening storage %#v", dsn)
+	}
+	return store, nil
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/alexthemitchell/community-attendance/dateparse"
+)
+
+// defaultLocation is the time zone of events that don't name one, and the
+// one other times are shown in.
+var defaultLocation = time.Local
+
+// AddTimeZoneFlag adds the global --default-time-zone flag setting
+// defaultLocation.
+func AddTimeZoneFlag(app *kingpin.Application) {
+	var name string
+	app.Flag("default-time-zone", "the IANA time zone of events that don't name one, such as America/Los_Angeles").
+		Envar("ATTENDANCE_TIME_ZONE").Default("Local").StringVar(&name)
+	app.Action(func(*kingpin.ParseContext) error {
+		location, err := loadLocation(name)
+		if err != nil {
+			return err
+		}
+		defaultLocation = location
+		return nil
+	})
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown time zone %#v", name)
+	}
+	return location, nil
+}
+
+// parseImportTime reads when an event being imported starts, which needs a
+// time of day. It may end with a zone abbreviation such as PST, which is
+// returned rather than used: abbreviations are ambiguous, and the event's
+// time zone decides the offset.
+func parseImportTime(value string, reference time.Time) (date dateparse.Date, abbreviation string, err error) {
+	value = strings.TrimSpace(value)
+	if i := strings.LastIndex(value, " "); i >= 0 && isZoneAbbreviation(value[i+1:]) {
+		value, abbreviation = strings.TrimSpace(value[:i]), value[i+1:]
+	}
+	date, err = dateparse.Parse(value, reference)
+	if err != nil {
+		return date, "", errors.Wrap(err, "error parsing event time")
+	}
+	if !date.HasClock() {
+		return date, "", errors.Errorf("the event time needs a time of day, as in \"%s 6:30PM\"", value)
+	}
+	return date, abbreviation, nil
+}
+
+func isZoneAbbreviation(s string) bool {
+	if len(s) < 2 || s == "AM" || s == "PM" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImportTime(t *testing.T) {
+	reference := time.Date(2019, time.March, 14, 12, 0, 0, 0, time.UTC)
+	wall := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	for _, value := range []string{"March 14, 2019 6:30PM", "March 14, 2019 6:30PM PDT", " March 14, 2019 6:30PM PST ", "2019-03-14 18:30", "today 6:30 PM"} {
+		parsed, _, err := parseImportTime(value, reference)
+		assert.NoError(t, err, value)
+		assert.Equal(t, wall, parsed.Wall(), value)
+	}
+	_, abbreviation, err := parseImportTime("March 14, 2019 6:30PM PST", reference)
+	assert.NoError(t, err)
+	assert.Equal(t, "PST", abbreviation)
+
+	_, err = loadLocation("Mars/Olympus_Mons")
+	assert.EqualError(t, err, `unknown time zone "Mars/Olympus_Mons": unknown time zone Mars/Olympus_Mons`)
+	_, _, err = parseImportTime("March 14, 2019", reference)
+	assert.EqualError(t, err, `the event time needs a time of day, as in "March 14, 2019 6:30PM"`)
+	_, _, err = parseImportTime("March 14, 2019 6:30", reference)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	guestListHeader = []string{"Legal Name", "RSVP", "Host"}
+	followUpHeader  = []string{"Preferred Name", "User ID", "Profile URL"}
+)
+
+func (e GuestListEntry) guestRecord() []string {
+	return []string{e.LegalName, e.RSVPStatus(), e.HostStatus()}
+}
+
+func (e GuestListEntry) followUpRecord() []string {
+	return []string{e.PreferredName, e.UserID, e.ProfileURL}
+}
+
+func writeCSV(w io.Writer, header []string, records [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return errors.Wrap(err, "error writing CSV header")
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return errors.Wrap(err, "error writing CSV records")
+	}
+	return nil
+}
+
+// WriteCSV writes the admissible guests as CSV.
+func (g *GuestList) WriteCSV(w io.Writer) error {
+	var records [][]string
+	for _, guest := range g.Guests {
+		records = append(records, guest.guestRecord())
+	}
+	return writeCSV(w, guestListHeader, records)
+}
+
+// WriteFollowUpCSV writes the guests without a legal name as CSV.
+func (g *GuestList) WriteFollowUpCSV(w io.Writer) error {
+	var records [][]string
+	for _, guest := range g.NeedsFollowUp {
+		records = append(records, guest.followUpRecord())
+	}
+	return writeCSV(w, followUpHeader, records)
+}
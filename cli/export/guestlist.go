@@ -0,0 +1,96 @@
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// GuestListEntry is one person on a building-security guest list.
+type GuestListEntry struct {
+	LegalName     string
+	PreferredName string
+	UserID        string
+	ProfileURL    string
+	RSVP          bool
+	Host          bool
+}
+
+// RSVPStatus renders the RSVP flag the way the front desk reads it.
+func (e GuestListEntry) RSVPStatus() string {
+	if e.RSVP {
+		return "Yes"
+	}
+	return "No"
+}
+
+// HostStatus renders the host flag the way the front desk reads it.
+func (e GuestListEntry) HostStatus() string {
+	if e.Host {
+		return "Host"
+	}
+	return ""
+}
+
+// GuestList is the legal-name list a venue's front desk needs for an event.
+// Guests without a legal name can't be admitted, so they are kept apart in
+// NeedsFollowUp for an organizer to chase up.
+type GuestList struct {
+	Event         *models.Event
+	Guests        []GuestListEntry
+	NeedsFollowUp []GuestListEntry
+}
+
+// NewGuestList builds the guest list for event from its attendance records.
+// Only people who RSVPed yes are included unless includeDeclined is set.
+// Guests are sorted by last name.
+func NewGuestList(event *models.Event, attendances []*models.Attendance, includeDeclined bool) *GuestList {
+	list := &GuestList{Event: event}
+	for _, attendance := range attendances {
+		if !attendance.RSVP() && !includeDeclined {
+			continue
+		}
+		attendee := attendance.Attendee()
+		entry := GuestListEntry{
+			LegalName:     strings.TrimSpace(attendee.LegalName()),
+			PreferredName: attendee.PreferredName(),
+			UserID:        attendee.UserID(),
+			RSVP:          attendance.RSVP(),
+			Host:          attendee.IsHost(),
+		}
+		if attendee.ProfileURL() != nil {
+			entry.ProfileURL = attendee.ProfileURL().String()
+		}
+		if entry.LegalName == "" {
+			list.NeedsFollowUp = append(list.NeedsFollowUp, entry)
+		} else {
+			list.Guests = append(list.Guests, entry)
+		}
+	}
+	sort.SliceStable(list.Guests, func(i, j int) bool {
+		return lessByLastName(list.Guests[i].LegalName, list.Guests[j].LegalName)
+	})
+	sort.SliceStable(list.NeedsFollowUp, func(i, j int) bool {
+		return strings.ToLower(list.NeedsFollowUp[i].PreferredName) < strings.ToLower(list.NeedsFollowUp[j].PreferredName)
+	})
+	return list
+}
+
+// lastName treats the final word of a name as the last name, which is what
+// the front desk files by for the common "First Middle Last" form.
+func lastName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+func lessByLastName(a, b string) bool {
+	lastA, lastB := strings.ToLower(lastName(a)), strings.ToLower(lastName(b))
+	if lastA != lastB {
+		return lastA < lastB
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func testGuestList() *GuestList {
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	attendance := func(preferred, legal, userID string, rsvp, host bool) *models.Attendance {
+		return models.NewAttendance(models.NewAttendee(preferred, legal, userID, nil, nil, host), event, rsvp, nil)
+	}
+	return NewGuestList(event, []*models.Attendance{
+		attendance("Alex", "Alex Mitchell", "user 1", true, true),
+		attendance("Dubie", "The Dubester", "user 2", true, false),
+		attendance("Walk-in", "", "user 3", true, false),
+		attendance("Jo", "Jo Anne Adams", "user 4", true, false),
+		attendance("Nope", "Not Coming", "user 5", false, false),
+	}, false)
+}
+
+func TestNewGuestListSortsByLastName(t *testing.T) {
+	list := testGuestList()
+	var names []string
+	for _, guest := range list.Guests {
+		names = append(names, guest.LegalName)
+	}
+	assert.Equal(t, []string{"Jo Anne Adams", "The Dubester", "Alex Mitchell"}, names)
+	if assert.Len(t, list.NeedsFollowUp, 1) {
+		assert.Equal(t, "user 3", list.NeedsFollowUp[0].UserID)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, testGuestList().WriteCSV(&b))
+	assert.Equal(t, "Legal Name,RSVP,Host\nJo Anne Adams,Yes,\nThe Dubester,Yes,\nAlex Mitchell,Yes,Host\n", b.String())
+}
+
+func TestWriteText(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, testGuestList().WriteText(&b))
+	assert.Contains(t, b.String(), "[ ] Alex Mitchell  Yes   Host\n")
+	assert.Contains(t, b.String(), "Needs follow-up: 1 without a legal name\n")
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, testGuestList().WriteXLSX(&b))
+	assert.True(t, bytes.HasPrefix(b.Bytes(), []byte("PK")))
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	printTimeFormat = "Monday, January 2, 2006 3:04 PM"
+	// printWidth fits a portrait page in a monospaced font.
+	printWidth = 78
+)
+
+// WriteText writes a fixed-width layout meant to be printed for the front
+// desk, with a box to tick for each guest and the follow-up list at the end.
+func (g *GuestList) WriteText(w io.Writer) error {
+	nameWidth := len("Legal Name")
+	for _, guest := range g.Guests {
+		if len(guest.LegalName) > nameWidth {
+			nameWidth = len(guest.LegalName)
+		}
+	}
+	lineFormat := fmt.Sprintf("%%-3s %%-%ds  %%-4s  %%s\n", nameWidth)
+
+	var b strings.Builder
+	fmt.Fprintln(&b, g.Event.Name())
+	if g.Event.Time() != nil {
+		fmt.Fprintln(&b, g.Event.Time().Format(printTimeFormat))
+	}
+	fmt.Fprintf(&b, "%d guests\n", len(g.Guests))
+	fmt.Fprintln(&b, strings.Repeat("=", printWidth))
+	fmt.Fprintf(&b, lineFormat, "", "Legal Name", "RSVP", "Host")
+	fmt.Fprintln(&b, strings.Repeat("-", printWidth))
+	for _, guest := range g.Guests {
+		fmt.Fprintf(&b, lineFormat, "[ ]", guest.LegalName, guest.RSVPStatus(), guest.HostStatus())
+	}
+
+	if len(g.NeedsFollowUp) > 0 {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "Needs follow-up: %d without a legal name\n", len(g.NeedsFollowUp))
+		fmt.Fprintln(&b, strings.Repeat("-", printWidth))
+		for _, guest := range g.NeedsFollowUp {
+			fmt.Fprintf(&b, "    %s (%s)\n", guest.PreferredName, guest.UserID)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
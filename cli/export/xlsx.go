@@ -0,0 +1,68 @@
+package export
+
+import (
+	"io"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/pkg/errors"
+)
+
+const (
+	guestListSheet = "Guest List"
+	followUpSheet  = "Needs Follow-up"
+)
+
+func writeSheet(f *excelize.File, sheet string, header []string, records [][]string, boldStyle int) error {
+	for r, record := range append([][]string{header}, records...) {
+		for c, value := range record {
+			axis, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellStr(sheet, axis, value); err != nil {
+				return err
+			}
+		}
+	}
+	last, err := excelize.ColumnNumberToName(len(header))
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", last+"1", boldStyle); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "A", last, 30); err != nil {
+		return err
+	}
+	return f.SetPanes(sheet, `{"freeze":true,"split":false,"x_split":0,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft"}`)
+}
+
+// WriteXLSX writes a workbook with the guest list on its first sheet and the
+// guests needing follow-up on a second.
+func (g *GuestList) WriteXLSX(w io.Writer) error {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", guestListSheet)
+	f.NewSheet(followUpSheet)
+	bold, err := f.NewStyle(`{"font":{"bold":true}}`)
+	if err != nil {
+		return errors.Wrap(err, "error creating header style")
+	}
+
+	var guests [][]string
+	for _, guest := range g.Guests {
+		guests = append(guests, guest.guestRecord())
+	}
+	if err := writeSheet(f, guestListSheet, guestListHeader, guests, bold); err != nil {
+		return errors.Wrap(err, "error writing guest list sheet")
+	}
+	var followUp [][]string
+	for _, guest := range g.NeedsFollowUp {
+		followUp = append(followUp, guest.followUpRecord())
+	}
+	if err := writeSheet(f, followUpSheet, followUpHeader, followUp, bold); err != nil {
+		return errors.Wrap(err, "error writing follow-up sheet")
+	}
+	f.SetActiveSheet(f.GetSheetIndex(guestListSheet))
+
+	return errors.Wrap(f.Write(w), "error writing workbook")
+}
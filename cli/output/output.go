@@ -0,0 +1,238 @@
+// Package output renders command results as tables or as data for other
+// tools.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+)
+
+// Column is one field of a table. Key is the stable field name used by the
+// data formats; Header is for people. Layout, if set, is how times in the
+// column are shown in the table format.
+type Column struct {
+	Key    string
+	Header string
+	Layout string
+}
+
+// Table holds rows of values in column order. Values may be strings,
+// bools, ints, float64s, time.Times or nil *time.Times.
+type Table struct {
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// Formats lists the formats Write accepts, the default first.
+func Formats() []string {
+	return []string{"table", "json", "csv", "tsv", "yaml"}
+}
+
+// Write renders t in format. Color is only used by the table format, and
+// should only be asked for when w is a terminal.
+func Write(w io.Writer, format string, t *Table, color bool) error {
+	switch format {
+	case "table":
+		return writeTable(w, t, color)
+	case "json":
+		return writeJSON(w, t)
+	case "csv":
+		return writeDelimited(w, t, ',')
+	case "tsv":
+		return writeDelimited(w, t, '\t')
+	case "yaml":
+		return writeYAML(w, t)
+	}
+	return errors.Errorf("unknown output format %#v", format)
+}
+
+// IsTerminal reports whether f is a terminal rather than a pipe or file.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// normalize dereferences time pointers, so nil times become nil.
+func normalize(v interface{}) interface{} {
+	if t, ok := v.(*time.Time); ok {
+		if t == nil {
+			return nil
+		}
+		return *t
+	}
+	return v
+}
+
+// text renders a value for the data formats.
+func text(v interface{}) string {
+	switch v := normalize(v).(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func displayText(v interface{}, c Column) string {
+	switch v := normalize(v).(type) {
+	case bool:
+		if v {
+			return "Yes"
+		}
+		return "No"
+	case time.Time:
+		if c.Layout != "" {
+			return v.Format(c.Layout)
+		}
+	}
+	return text(v)
+}
+
+// writeTable aligns the cells in columns two spaces apart. The header is
+// bold when color is on; it is padded before coloring so the escape codes
+// don't upset the alignment.
+func writeTable(w io.Writer, t *Table, color bool) error {
+	lines := make([][]string, 0, len(t.Rows)+1)
+	var headers []string
+	for _, c := range t.Columns {
+		headers = append(headers, c.Header)
+	}
+	lines = append(lines, headers)
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = displayText(v, t.Columns[i])
+		}
+		lines = append(lines, cells)
+	}
+
+	widths := make([]int, len(t.Columns))
+	for _, line := range lines {
+		for i, cell := range line {
+			if n := runewidth.StringWidth(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for l, line := range lines {
+		var b strings.Builder
+		for i, cell := range line {
+			b.WriteString(cell)
+			if i < len(line)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-runewidth.StringWidth(cell)+2))
+			}
+		}
+		text := strings.TrimRight(b.String(), " ")
+		if l == 0 && color {
+			text = aurora.Bold(text).String()
+		}
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeDelimited(w io.Writer, t *Table, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	var keys []string
+	for _, c := range t.Columns {
+		keys = append(keys, c.Key)
+	}
+	if err := cw.Write(keys); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = text(v)
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes an array of objects, keeping the fields in column order.
+func writeJSON(w io.Writer, t *Table) error {
+	if len(t.Rows) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("[\n")
+	for r, row := range t.Rows {
+		b.WriteString("  {")
+		for i, v := range row {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			key, _ := json.Marshal(t.Columns[i].Key)
+			value, err := json.Marshal(normalize(v))
+			if err != nil {
+				return errors.Wrapf(err, "error encoding %s", t.Columns[i].Key)
+			}
+			b.Write(key)
+			b.WriteString(": ")
+			b.Write(value)
+		}
+		b.WriteString("}")
+		if r < len(t.Rows)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeYAML writes a sequence of mappings. Strings are written as
+// double-quoted scalars, which YAML reads the same way JSON does, so no
+// value can be mistaken for another type.
+func writeYAML(w io.Writer, t *Table) error {
+	if len(t.Rows) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+	var b strings.Builder
+	for _, row := range t.Rows {
+		for i, v := range row {
+			prefix := "  "
+			if i == 0 {
+				prefix = "- "
+			}
+			var value string
+			switch v := normalize(v).(type) {
+			case nil:
+				value = "null"
+			case time.Time:
+				value = v.Format(time.RFC3339)
+			case string:
+				quoted, _ := json.Marshal(v)
+				value = string(quoted)
+			default:
+				value = fmt.Sprint(v)
+			}
+			fmt.Fprintf(&b, "%s%s: %s\n", prefix, t.Columns[i].Key, value)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
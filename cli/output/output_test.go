@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTable() *Table {
+	joined := time.Date(2017, time.July, 8, 0, 0, 0, 0, time.UTC)
+	return &Table{
+		Columns: []Column{
+			{Key: "name", Header: "Name"},
+			{Key: "joined", Header: "Joined", Layout: "2006-01-02"},
+			{Key: "host", Header: "Host?"},
+			{Key: "count", Header: "Count"},
+		},
+		Rows: [][]interface{}{
+			{"Alex \"Al\" Mitchell", &joined, true, 3},
+			{"Dubie", (*time.Time)(nil), false, 0},
+		},
+	}
+}
+
+func write(t *testing.T, format string, color bool) string {
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, format, testTable(), color))
+	return b.String()
+}
+
+func TestWriteTable(t *testing.T) {
+	assert.Equal(t, ""+
+		"Name                Joined      Host?  Count\n"+
+		"Alex \"Al\" Mitchell  2017-07-08  Yes    3\n"+
+		"Dubie                           No     0\n",
+		write(t, "table", false))
+	assert.Contains(t, write(t, "table", true), "\x1b[1mName")
+}
+
+func TestWriteTableAlignsWideCharacters(t *testing.T) {
+	table := &Table{
+		Columns: []Column{{Key: "name", Header: "Name"}, {Key: "count", Header: "Count"}},
+		Rows:    [][]interface{}{{"山田太郎", 3}, {"Jo", 1}},
+	}
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, "table", table, false))
+	assert.Equal(t, ""+
+		"Name      Count\n"+
+		"山田太郎  3\n"+
+		"Jo        1\n",
+		b.String())
+}
+
+func TestWriteCSV(t *testing.T) {
+	assert.Equal(t, ""+
+		"name,joined,host,count\n"+
+		"\"Alex \"\"Al\"\" Mitchell\",2017-07-08T00:00:00Z,true,3\n"+
+		"Dubie,,false,0\n",
+		write(t, "csv", true))
+	assert.Contains(t, write(t, "tsv", false), "name\tjoined\thost\tcount\n")
+}
+
+func TestWriteJSON(t *testing.T) {
+	assert.Equal(t, ""+
+		"[\n"+
+		"  {\"name\": \"Alex \\\"Al\\\" Mitchell\", \"joined\": \"2017-07-08T00:00:00Z\", \"host\": true, \"count\": 3},\n"+
+		"  {\"name\": \"Dubie\", \"joined\": null, \"host\": false, \"count\": 0}\n"+
+		"]\n",
+		write(t, "json", false))
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, "json", &Table{}, false))
+	assert.Equal(t, "[]\n", b.String())
+}
+
+func TestWriteYAML(t *testing.T) {
+	assert.Equal(t, ""+
+		"- name: \"Alex \\\"Al\\\" Mitchell\"\n"+
+		"  joined: 2017-07-08T00:00:00Z\n"+
+		"  host: true\n"+
+		"  count: 3\n"+
+		"- name: \"Dubie\"\n"+
+		"  joined: null\n"+
+		"  host: false\n"+
+		"  count: 0\n",
+		write(t, "yaml", false))
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	var b bytes.Buffer
+	assert.Error(t, Write(&b, "xml", testTable(), false))
+}
@@ -0,0 +1,123 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnMapping names the header of each column the reader understands.
+// Headers are matched case-insensitively after trimming whitespace, except
+// LegalNameQuestion which only has to appear somewhere in the header, since
+// organizers word their "legal name" RSVP question differently.
+type ColumnMapping struct {
+	PreferredName     string
+	UserID            string
+	Host              string
+	RSVP              string
+	RSVPTime          string
+	JoinedDate        string
+	ProfileURL        string
+	Email             string
+	LegalNameQuestion string
+}
+
+// DefaultMeetupColumns matches the headers of a Meetup attendee export.
+var DefaultMeetupColumns = ColumnMapping{
+	PreferredName:     "Name",
+	UserID:            "User ID",
+	Host:              "Event Host",
+	RSVP:              "RSVP",
+	RSVPTime:          "RSVPed on",
+	JoinedDate:        "Joined Group on",
+	ProfileURL:        "URL of Member Profile",
+	Email:             "Email",
+	LegalNameQuestion: "as it appears on your ID",
+}
+
+// MissingColumnsError is returned when a header row lacks required columns.
+type MissingColumnsError struct {
+	Columns []string
+}
+
+func (e *MissingColumnsError) Error() string {
+	return fmt.Sprintf("missing required columns: %s", strings.Join(e.Columns, ", "))
+}
+
+// columnIndexes holds the position of each mapped column, or -1 if the
+// column isn't present.
+type columnIndexes struct {
+	preferredName int
+	userID        int
+	host          int
+	rsvp          int
+	rsvpTime      int
+	joinedDate    int
+	profileURL    int
+	email         int
+	legalName     int
+}
+
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.TrimSpace(header))
+}
+
+// indexesFromHeader locates the mapped columns in header. The preferred
+// name, user ID and RSVP columns are required.
+func (m ColumnMapping) indexesFromHeader(header []string) (*columnIndexes, error) {
+	find := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		for i, cell := range header {
+			if normalizeHeader(cell) == normalizeHeader(name) {
+				return i
+			}
+		}
+		return -1
+	}
+	indexes := &columnIndexes{
+		preferredName: find(m.PreferredName),
+		userID:        find(m.UserID),
+		host:          find(m.Host),
+		rsvp:          find(m.RSVP),
+		rsvpTime:      find(m.RSVPTime),
+		joinedDate:    find(m.JoinedDate),
+		profileURL:    find(m.ProfileURL),
+		email:         find(m.Email),
+		legalName:     -1,
+	}
+	if question := normalizeHeader(m.LegalNameQuestion); question != "" {
+		for i, cell := range header {
+			if strings.Contains(normalizeHeader(cell), question) {
+				indexes.legalName = i
+				break
+			}
+		}
+	}
+
+	var missing []string
+	for _, required := range []struct {
+		name  string
+		index int
+	}{
+		{m.PreferredName, indexes.preferredName},
+		{m.UserID, indexes.userID},
+		{m.RSVP, indexes.rsvp},
+	} {
+		if required.index < 0 {
+			missing = append(missing, required.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingColumnsError{Columns: missing}
+	}
+	return indexes, nil
+}
+
+// cell returns row[index], or "" if the column is absent or the row short.
+func cell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
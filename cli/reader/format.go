@@ -0,0 +1,142 @@
+package reader
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// sniffLength is how much of a file DetectFormat reads for formats to
+// inspect.
+const sniffLength = 4096
+
+// Options tunes how a Format reads a file. Formats ignore options that
+// don't apply to them.
+type Options struct {
+	Columns ColumnMapping
+	// Sheet selects a worksheet of a workbook by name or 1-based position.
+	// The first sheet is used if it is empty.
+	Sheet string
+	// Location is the time zone of times in the file, which exports give
+	// as wall clock times. Times are read as UTC if it is nil.
+	Location *time.Location
+}
+
+func (o Options) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+// DefaultOptions reads Meetup exports.
+func DefaultOptions() Options {
+	return Options{Columns: DefaultMeetupColumns}
+}
+
+// Format is a file format attendance can be imported from.
+type Format interface {
+	// Name identifies the format on the command line.
+	Name() string
+	// Detect reports whether a file looks like this format, given its name
+	// and up to the first few kilobytes of its contents.
+	Detect(fileName string, head []byte) bool
+	// Parse reads every attendance record for event from the file. Rows that
+	// fail to parse are skipped and reported in the returned errors.
+	Parse(fileName string, event *models.Event, options Options) ([]*models.Attendance, []error)
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   []Format
+)
+
+// RegisterFormat makes a format available for detection and by name. Formats
+// are tried in the order they are registered. It panics if the name is
+// already taken.
+func RegisterFormat(format Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	for _, f := range formats {
+		if f.Name() == format.Name() {
+			panic("reader: RegisterFormat called twice for format " + format.Name())
+		}
+	}
+	formats = append(formats, format)
+}
+
+// FormatNames returns the sorted names of every registered format.
+func FormatNames() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	return formatNames()
+}
+
+// formatNames is FormatNames for callers already holding formatsMu.
+func formatNames() []string {
+	var names []string
+	for _, f := range formats {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatByName returns the registered format with the given name.
+func FormatByName(name string) (Format, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	for _, f := range formats {
+		if f.Name() == name {
+			return f, nil
+		}
+	}
+	return nil, errors.Errorf("unknown format %#v (known: %s)", name, strings.Join(formatNames(), ", "))
+}
+
+// DetectFormat returns the first registered format that recognizes the file.
+func DetectFormat(fileName string) (Format, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening file for read: %#v", fileName)
+	}
+	defer file.Close()
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, errors.Wrapf(err, "error reading file: %#v", fileName)
+	}
+	head = head[:n]
+
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	for _, f := range formats {
+		if f.Detect(fileName, head) {
+			return f, nil
+		}
+	}
+	return nil, errors.Errorf("unable to detect the format of %#v, please specify one", fileName)
+}
+
+// ParseFile reads attendance from fileName using the named format, or the
+// detected format if formatName is empty.
+func ParseFile(fileName, formatName string, event *models.Event, options Options) ([]*models.Attendance, []error) {
+	var format Format
+	var err error
+	if formatName == "" {
+		format, err = DetectFormat(fileName)
+	} else {
+		format, err = FormatByName(formatName)
+	}
+	if err != nil {
+		return nil, []error{err}
+	}
+	return format.Parse(fileName, event, options)
+}
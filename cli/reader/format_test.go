@@ -0,0 +1,30 @@
+package reader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestDetectFormat(t *testing.T) {
+	format, err := DetectFormat(happyPathSourceFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "meetup-tsv", format.Name())
+
+	assert.True(t, meetupTSVFormat{}.Detect("export.txt", []byte("Name\tUser ID\tRSVP\nAlex\tuser 1\tYes\n")))
+	assert.False(t, meetupTSVFormat{}.Detect("export.txt", []byte("Name,Email\nAlex,alex@example.com\n")))
+}
+
+func TestParseFileByName(t *testing.T) {
+	now := time.Now()
+	event := models.NewEvent("Test Event", "1234", &now)
+	attendance, errs := ParseFile(happyPathSourceFile, "meetup-tsv", event, DefaultOptions())
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, 4, len(attendance))
+
+	_, errs = ParseFile(happyPathSourceFile, "no-such-format", event, DefaultOptions())
+	assert.Equal(t, 1, len(errs))
+}
@@ -0,0 +1,38 @@
+package reader
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func init() {
+	RegisterFormat(meetupTSVFormat{})
+}
+
+// meetupTSVFormat reads the tab separated attendee list Meetup exports for
+// an event.
+type meetupTSVFormat struct{}
+
+func (meetupTSVFormat) Name() string {
+	return "meetup-tsv"
+}
+
+func (meetupTSVFormat) Detect(fileName string, head []byte) bool {
+	if strings.EqualFold(filepath.Ext(fileName), ".tsv") {
+		return true
+	}
+	firstLine := head
+	if i := bytes.IndexAny(head, "\r\n"); i >= 0 {
+		firstLine = head[:i]
+	}
+	header := strings.Split(string(firstLine), "\t")
+	_, err := DefaultMeetupColumns.indexesFromHeader(header)
+	return err == nil
+}
+
+func (meetupTSVFormat) Parse(fileName string, event *models.Event, options Options) ([]*models.Attendance, []error) {
+	return parseTSVFile(fileName, event, options)
+}
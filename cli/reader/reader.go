@@ -9,12 +9,25 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/timezone"
 )
 
 const rsvpTimeLayout = "January _2, 2006 3:04 PM"
 const joinedDateLayout = "January _2, 2006"
 
+// ParseAttendanceFromFile reads a Meetup tab separated attendee export,
+// locating columns by the names in DefaultMeetupColumns.
 func ParseAttendanceFromFile(fileName string, event *models.Event) ([]*models.Attendance, []error) {
+	return ParseAttendanceFromFileWithColumns(fileName, event, DefaultMeetupColumns)
+}
+
+// ParseAttendanceFromFileWithColumns reads a tab separated attendee export
+// whose first row is a header, locating columns by the names in columns.
+func ParseAttendanceFromFileWithColumns(fileName string, event *models.Event, columns ColumnMapping) ([]*models.Attendance, []error) {
+	return parseTSVFile(fileName, event, Options{Columns: columns})
+}
+
+func parseTSVFile(fileName string, event *models.Event, options Options) ([]*models.Attendance, []error) {
 	file, err := os.Open(fileName)
 	if err != nil {
 		return nil, []error{
@@ -27,6 +40,7 @@ func ParseAttendanceFromFile(fileName string, event *models.Event) ([]*models.At
 
 	reader.Comma = '\t'
 	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
 
 	readData, err := reader.ReadAll()
 	if err != nil {
@@ -34,79 +48,89 @@ func ParseAttendanceFromFile(fileName string, event *models.Event) ([]*models.At
 			errors.Wrapf(err, "error reading tab separated data from file: %#v", fileName),
 		}
 	}
+	return ParseAttendanceFromRows(readData, event, options)
+}
+
+// ParseAttendanceFromRows converts already-read rows, the first of which must
+// be the header, into attendance records. Rows that fail to parse are
+// skipped and reported in the returned errors.
+func ParseAttendanceFromRows(rows [][]string, event *models.Event, options Options) ([]*models.Attendance, []error) {
+	rowNumbers := make([]int, len(rows))
+	for i := range rows {
+		rowNumbers[i] = i + 1
+	}
+	return parseNumberedRows(rows, rowNumbers, event, options)
+}
+
+// parseNumberedRows is ParseAttendanceFromRows for rows picked out of a
+// sheet, where rowNumbers holds the 1-based sheet row of each row so errors
+// point at the right place.
+func parseNumberedRows(rows [][]string, rowNumbers []int, event *models.Event, options Options) ([]*models.Attendance, []error) {
+	if len(rows) == 0 {
+		return nil, []error{errors.New("no header row found")}
+	}
+	indexes, err := options.Columns.indexesFromHeader(rows[0])
+	if err != nil {
+		return nil, []error{err}
+	}
 
 	var attendances []*models.Attendance
 	var errs []error
-	for rowIndex, row := range readData {
-		var preferredName string
-		var userID string
-		var legalName string
-		var profileURL *url.URL
-		var isHost bool
-		var rsvp bool
-		var rsvpTime time.Time
-		var joinedDate time.Time
-		for cellIndex, cellValue := range row {
-			switch cellIndex {
-			case 0:
-				preferredName = cellValue
-				break
-			case 1:
-				userID = cellValue
-				break
-			case 3:
-				isHost = (cellValue == "Yes")
-				break
-			case 4:
-				rsvp = (cellValue == "Yes")
-				break
-
-			case 6:
-				rsvpTime, err = time.Parse(rsvpTimeLayout, cellValue)
-				if err != nil {
-					errs = append(errs, errors.Wrapf(err, "error parsing RSVP time for row %d (%#v)", rowIndex, preferredName))
-					continue
-				}
-				break
-			case 7:
-				joinedDate, err = time.Parse(joinedDateLayout, cellValue)
-				if err != nil {
-					errs = append(errs, errors.Wrapf(err, "error parsing RSVP time for row %d (%#v)", rowIndex, preferredName))
-					continue
-				}
-				break
-			case 8:
-				profileURL, err = url.Parse(cellValue)
-				if err != nil {
-					errs = append(errs, errors.Wrapf(err, "error parsing profile URL for row %d (%#v)", rowIndex, preferredName))
-					continue
-				}
-				break
-
-			case 9:
-				legalName = cellValue
-				break
-			}
+	for i, row := range rows[1:] {
+		attendance, err := parseRow(row, indexes, event, options.location())
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parsing row %d (%#v)", rowNumbers[i+1], cell(row, indexes.preferredName)))
+			continue
 		}
-		attendee := models.NewAttendee(preferredName, legalName, userID, profileURL, &joinedDate, isHost)
-		att := models.NewAttendance(attendee, event, rsvp, &rsvpTime)
-		attendances = append(attendances, att)
+		attendances = append(attendances, attendance)
 	}
 
 	return attendances, errs
 }
 
-/*
+// parseTimeCell parses a date with or without a time of day. Times of day
+// are wall clock times in location, which mustn't be skipped by a daylight
+// saving change; dates alone are kept as midnight UTC. Empty cells have no
+// time.
+func parseTimeCell(value string, location *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	wall, err := time.Parse(rsvpTimeLayout, value)
+	if err != nil {
+		parsed, dateErr := time.Parse(joinedDateLayout, value)
+		if dateErr != nil {
+			return nil, err
+		}
+		return &parsed, nil
+	}
+	parsed, err := timezone.Date(wall, location)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
 
-0 Name
-1 User ID
-2 Title
-3 Event Host
-4 RSVP
-5 Guests
-6 RSVPed on
-7 Joined Group on
-8 URL of Member Profile
-9 (Mandatory) Please provide your name exactly as it appears on your ID here, even if we already have it. Due to security considerations, you cannot be admitted without completing this field.
+func parseRow(row []string, indexes *columnIndexes, event *models.Event, location *time.Location) (*models.Attendance, error) {
+	preferredName := cell(row, indexes.preferredName)
+	userID := cell(row, indexes.userID)
+	legalName := cell(row, indexes.legalName)
+	isHost := cell(row, indexes.host) == "Yes"
+	rsvp := cell(row, indexes.rsvp) == "Yes"
 
-*/
+	rsvpTime, err := parseTimeCell(cell(row, indexes.rsvpTime), location)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing RSVP time")
+	}
+	joinedDate, err := parseTimeCell(cell(row, indexes.joinedDate), location)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing joined date")
+	}
+	profileURL, err := url.Parse(cell(row, indexes.profileURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing profile URL")
+	}
+
+	attendee := models.NewAttendee(preferredName, legalName, userID, profileURL, joinedDate, isHost).WithEmail(cell(row, indexes.email))
+	return models.NewAttendance(attendee, event, rsvp, rsvpTime), nil
+}
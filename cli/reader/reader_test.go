@@ -9,7 +9,9 @@ import (
 )
 
 const (
-	happyPathSourceFile = "./test_files/validexample.tsv"
+	happyPathSourceFile      = "./test_files/validexample.tsv"
+	reorderedSourceFile      = "./test_files/reordered.tsv"
+	missingColumnsSourceFile = "./test_files/missingcolumns.tsv"
 )
 
 func TestParseAttendanceFromFileHappyPath(t *testing.T) {
@@ -19,4 +21,76 @@ func TestParseAttendanceFromFileHappyPath(t *testing.T) {
 	assert.Equal(t, 0, len(errs))
 	assert.Equal(t, 4, len(attendance))
 
+	first := attendance[0].Attendee()
+	assert.Equal(t, "Alex Mitchell", first.PreferredName())
+	assert.Equal(t, "user 209174142", first.UserID())
+	assert.Equal(t, "Alex Mitchell", first.LegalName())
+	assert.True(t, first.IsHost())
+	assert.True(t, attendance[0].RSVP())
+	assert.Equal(t, "The Dubester", attendance[1].Attendee().LegalName())
+	assert.False(t, attendance[3].RSVP())
+}
+
+func TestParseAttendanceFromFileReorderedColumns(t *testing.T) {
+	now := time.Now()
+	event := models.NewEvent("Test Event", "1234", &now)
+	expected, errs := ParseAttendanceFromFile(happyPathSourceFile, event)
+	assert.Equal(t, 0, len(errs))
+	attendance, errs := ParseAttendanceFromFile(reorderedSourceFile, event)
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, expected, attendance)
+}
+
+func TestParseAttendanceFromFileMissingColumns(t *testing.T) {
+	now := time.Now()
+	event := models.NewEvent("Test Event", "1234", &now)
+	attendance, errs := ParseAttendanceFromFile(missingColumnsSourceFile, event)
+	assert.Nil(t, attendance)
+	if assert.Equal(t, 1, len(errs)) {
+		missing, ok := errs[0].(*MissingColumnsError)
+		if assert.True(t, ok) {
+			assert.Equal(t, []string{"User ID", "RSVP"}, missing.Columns)
+		}
+	}
+}
+
+func TestParseAttendanceFromRowsEmail(t *testing.T) {
+	now := time.Now()
+	event := models.NewEvent("Test Event", "1234", &now)
+	rows := [][]string{
+		{"Name", "User ID", "RSVP", "Email"},
+		{"Alex", "user 1", "Yes", " alex@example.com "},
+	}
+	attendance, errs := ParseAttendanceFromRows(rows, event, DefaultOptions())
+	assert.Empty(t, errs)
+	if assert.Len(t, attendance, 1) {
+		assert.Equal(t, "alex@example.com", attendance[0].Attendee().Email())
+	}
+}
+
+func TestParseAttendanceFromRowsLocation(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+	now := time.Now()
+	event := models.NewEvent("Test Event", "1234", &now)
+	rows := [][]string{
+		{"Name", "User ID", "RSVP", "RSVPed on", "Joined Group on"},
+		{"Alex", "user 1", "Yes", "March 9, 2019 6:30 PM", "March 9, 2019"},
+		{"Dubie", "user 2", "Yes", "March 10, 2019 6:30 PM", ""},
+		// 2:30 AM didn't happen that day.
+		{"Jo", "user 3", "Yes", "March 10, 2019 2:30 AM", ""},
+	}
+	options := DefaultOptions()
+	options.Location = losAngeles
+	attendance, errs := ParseAttendanceFromRows(rows, event, options)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "row 4")
+	}
+	if assert.Len(t, attendance, 2) {
+		// Clocks sprang forward between the two RSVPs.
+		assert.Equal(t, time.Date(2019, time.March, 10, 2, 30, 0, 0, time.UTC), attendance[0].RSVPTime().UTC())
+		assert.Equal(t, time.Date(2019, time.March, 11, 1, 30, 0, 0, time.UTC), attendance[1].RSVPTime().UTC())
+		// Dates alone aren't moved.
+		assert.Equal(t, time.Date(2019, time.March, 9, 0, 0, 0, 0, time.UTC), *attendance[0].Attendee().JoinedDate())
+	}
 }
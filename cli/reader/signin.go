@@ -0,0 +1,140 @@
+package reader
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tealeg/xlsx"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+)
+
+// signInHeaders maps the normalized headers a sign-in sheet may use to the
+// field they fill.
+var signInHeaders = map[string]string{
+	"name":           "name",
+	"preferred name": "name",
+	"legal name":     "legal name",
+	"full name":      "legal name",
+	"user id":        "user id",
+	"email":          "email",
+	"email address":  "email",
+	"time":           "time",
+	"signed in":      "time",
+	"signed in at":   "time",
+	"checked in":     "time",
+	"checked in at":  "time",
+}
+
+// signInIndexes finds the sign-in columns in a header row. Columns
+// containing legalNameQuestion also count as the legal name.
+func signInIndexes(header []string, legalNameQuestion string) (map[string]int, error) {
+	indexes := map[string]int{}
+	question := normalizeHeader(legalNameQuestion)
+	for i, h := range header {
+		field, ok := signInHeaders[normalizeHeader(h)]
+		if !ok && question != "" && strings.Contains(normalizeHeader(h), question) {
+			field, ok = "legal name", true
+		}
+		if _, taken := indexes[field]; ok && !taken {
+			indexes[field] = i
+		}
+	}
+	for _, field := range []string{"name", "legal name", "user id", "email"} {
+		if _, ok := indexes[field]; ok {
+			return indexes, nil
+		}
+	}
+	return nil, errors.New("sign-in sheet needs a Name, Legal Name, User ID or Email column")
+}
+
+func signInCell(row []string, indexes map[string]int, field string) string {
+	index, ok := indexes[field]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(cell(row, index))
+}
+
+// ReadSignInSheet reads the people who signed in at an event from a comma
+// or tab separated file or a workbook. The sheet must have a header row
+// with at least one of Name, Legal Name, User ID or Email, and may have a
+// Time.
+func ReadSignInSheet(fileName string, options Options) ([]*checkin.SignIn, error) {
+	rows, err := readSignInRows(fileName, options.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	headerRow := 0
+	for headerRow < len(rows) && isBlankRow(rows[headerRow]) {
+		headerRow++
+	}
+	if headerRow == len(rows) {
+		return nil, errors.New("no header row found")
+	}
+	indexes, err := signInIndexes(rows[headerRow], options.Columns.LegalNameQuestion)
+	if err != nil {
+		return nil, err
+	}
+
+	var signIns []*checkin.SignIn
+	for r := headerRow + 1; r < len(rows); r++ {
+		row := rows[r]
+		if isBlankRow(row) {
+			continue
+		}
+		signIn := &checkin.SignIn{
+			Name:      signInCell(row, indexes, "name"),
+			LegalName: signInCell(row, indexes, "legal name"),
+			UserID:    signInCell(row, indexes, "user id"),
+			Email:     signInCell(row, indexes, "email"),
+		}
+		signIn.Time, err = parseTimeCell(signInCell(row, indexes, "time"), options.location())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing time in row %d", r+1)
+		}
+		if signIn.DisplayName() == "" {
+			continue
+		}
+		signIns = append(signIns, signIn)
+	}
+	return signIns, nil
+}
+
+func readSignInRows(fileName, sheetSelection string) ([][]string, error) {
+	if strings.EqualFold(filepath.Ext(fileName), ".xlsx") {
+		file, err := xlsx.OpenFile(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error opening workbook: %#v", fileName)
+		}
+		sheet, err := selectSheet(file, sheetSelection)
+		if err != nil {
+			return nil, err
+		}
+		var rows [][]string
+		for _, row := range unmergedCells(sheet) {
+			rows = append(rows, cellStrings(row, map[int]bool{}, file.Date1904))
+		}
+		return rows, nil
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening file for read: %#v", fileName)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	if strings.EqualFold(filepath.Ext(fileName), ".tsv") {
+		reader.Comma = '\t'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading sign-in sheet: %#v", fileName)
+	}
+	return rows, nil
+}
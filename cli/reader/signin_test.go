@@ -0,0 +1,41 @@
+package reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSignInSheet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "signin.tsv")
+	contents := "User ID\tName\tTime\nuser 1\tDubie\tMarch 14, 2019 6:45 PM\n\t\t\n\tAlex\t\n"
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(contents), 0644))
+
+	signIns, err := ReadSignInSheet(fileName, DefaultOptions())
+	assert.NoError(t, err)
+	if assert.Len(t, signIns, 2) {
+		assert.Equal(t, "user 1", signIns[0].UserID)
+		assert.Equal(t, "Dubie", signIns[0].Name)
+		assert.Equal(t, time.Date(2019, time.March, 14, 18, 45, 0, 0, time.UTC), *signIns[0].Time)
+		assert.Equal(t, "Alex", signIns[1].Name)
+		assert.Nil(t, signIns[1].Time)
+	}
+
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("Time\nMarch 14, 2019 6:45 PM\n"), 0644))
+	_, err = ReadSignInSheet(fileName, DefaultOptions())
+	assert.Error(t, err)
+
+	// Rows are counted from the top of the sheet, blank ones included.
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("\t\nName\tTime\nDubie\tyesterday\n"), 0644))
+	_, err = ReadSignInSheet(fileName, DefaultOptions())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "row 3")
+	}
+}
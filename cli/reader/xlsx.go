@@ -0,0 +1,202 @@
+package reader
+
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tealeg/xlsx"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// maxHeaderSearchRows bounds how far down a sheet the reader looks for the
+// header row, to allow for title rows above it.
+const maxHeaderSearchRows = 10
+
+func init() {
+	RegisterFormat(xlsxFormat{})
+}
+
+// xlsxFormat reads an attendee list from an Excel workbook, such as a Meetup
+// export re-saved as .xlsx or a hand-made sign-in sheet.
+type xlsxFormat struct{}
+
+func (xlsxFormat) Name() string {
+	return "xlsx"
+}
+
+func (xlsxFormat) Detect(fileName string, head []byte) bool {
+	return strings.EqualFold(filepath.Ext(fileName), ".xlsx") ||
+		bytes.HasPrefix(head, []byte("PK\x03\x04"))
+}
+
+func (xlsxFormat) Parse(fileName string, event *models.Event, options Options) ([]*models.Attendance, []error) {
+	return ParseAttendanceFromXLSX(fileName, event, options)
+}
+
+// ParseAttendanceFromXLSX reads attendance from the sheet selected by
+// options.Sheet, using the same header-based column mapping as the tab
+// separated reader. The header doesn't have to be the first row: title rows
+// above it are skipped.
+func ParseAttendanceFromXLSX(fileName string, event *models.Event, options Options) ([]*models.Attendance, []error) {
+	file, err := xlsx.OpenFile(fileName)
+	if err != nil {
+		return nil, []error{
+			errors.Wrapf(err, "error opening workbook: %#v", fileName),
+		}
+	}
+	sheet, err := selectSheet(file, options.Sheet)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	cells := unmergedCells(sheet)
+	headerRow, indexes, err := findHeaderRow(cells, options.Columns)
+	if err != nil {
+		return nil, []error{errors.Wrapf(err, "error reading sheet %#v", sheet.Name)}
+	}
+
+	// Data starts below the header, including any header cells merged
+	// downwards.
+	firstDataRow := headerRow + 1
+	for _, c := range sheet.Rows[headerRow].Cells {
+		if headerRow+c.VMerge+1 > firstDataRow {
+			firstDataRow = headerRow + c.VMerge + 1
+		}
+	}
+
+	dateColumns := map[int]bool{indexes.rsvpTime: true, indexes.joinedDate: true}
+	rows := [][]string{cellStrings(cells[headerRow], nil, file.Date1904)}
+	rowNumbers := []int{headerRow + 1}
+	for r := firstDataRow; r < len(cells); r++ {
+		row := cellStrings(cells[r], dateColumns, file.Date1904)
+		if isBlankRow(row) {
+			continue
+		}
+		rows = append(rows, row)
+		rowNumbers = append(rowNumbers, r+1)
+	}
+	return parseNumberedRows(rows, rowNumbers, event, options)
+}
+
+// selectSheet finds a sheet by name, or failing that by its 1-based position
+// in the workbook. An empty selection means the first sheet.
+func selectSheet(file *xlsx.File, selection string) (*xlsx.Sheet, error) {
+	if len(file.Sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+	if selection == "" {
+		return file.Sheets[0], nil
+	}
+	if sheet, ok := file.Sheet[selection]; ok {
+		return sheet, nil
+	}
+	if index, err := strconv.Atoi(selection); err == nil {
+		if index < 1 || index > len(file.Sheets) {
+			return nil, errors.Errorf("sheet %d out of range, workbook has %d sheets", index, len(file.Sheets))
+		}
+		return file.Sheets[index-1], nil
+	}
+	var names []string
+	for _, sheet := range file.Sheets {
+		names = append(names, sheet.Name)
+	}
+	return nil, errors.Errorf("no sheet named %#v (sheets: %s)", selection, strings.Join(names, ", "))
+}
+
+// unmergedCells returns the sheet's cells with every cell covered by a
+// merged range replaced by the range's top-left cell.
+func unmergedCells(sheet *xlsx.Sheet) [][]*xlsx.Cell {
+	cells := make([][]*xlsx.Cell, len(sheet.Rows))
+	for r, row := range sheet.Rows {
+		if row == nil {
+			continue
+		}
+		cells[r] = append([]*xlsx.Cell(nil), row.Cells...)
+	}
+	for r, row := range sheet.Rows {
+		if row == nil {
+			continue
+		}
+		for c, origin := range row.Cells {
+			for dr := 0; dr <= origin.VMerge; dr++ {
+				for dc := 0; dc <= origin.HMerge; dc++ {
+					if (dr == 0 && dc == 0) || r+dr >= len(cells) {
+						continue
+					}
+					for len(cells[r+dr]) <= c+dc {
+						cells[r+dr] = append(cells[r+dr], nil)
+					}
+					cells[r+dr][c+dc] = origin
+				}
+			}
+		}
+	}
+	return cells
+}
+
+// findHeaderRow returns the first row within maxHeaderSearchRows that has
+// every required column. If there is none, the error describes the first
+// non-blank row.
+func findHeaderRow(cells [][]*xlsx.Cell, columns ColumnMapping) (int, *columnIndexes, error) {
+	var firstErr error
+	for r := 0; r < len(cells) && r < maxHeaderSearchRows; r++ {
+		header := cellStrings(cells[r], nil, false)
+		if isBlankRow(header) {
+			continue
+		}
+		indexes, err := columns.indexesFromHeader(header)
+		if err == nil {
+			return r, indexes, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = errors.New("no header row found")
+	}
+	return 0, nil, firstErr
+}
+
+// cellStrings renders a row of cells as text. Cells formatted as dates, and
+// numeric cells in dateColumns, hold Excel serial dates and are rendered in
+// the layouts parseRow expects.
+func cellStrings(row []*xlsx.Cell, dateColumns map[int]bool, date1904 bool) []string {
+	values := make([]string, len(row))
+	for i, c := range row {
+		if c == nil {
+			continue
+		}
+		if dateColumns != nil && (dateColumns[i] || c.IsTime()) {
+			if serial, err := strconv.ParseFloat(strings.TrimSpace(c.Value), 64); err == nil {
+				values[i] = formatExcelDate(xlsx.TimeFromExcelTime(serial, date1904))
+				continue
+			}
+		}
+		values[i] = c.Value
+	}
+	return values
+}
+
+func formatExcelDate(t time.Time) string {
+	// Round away the floating point error in the serial's fraction.
+	t = t.Round(time.Minute)
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format(joinedDateLayout)
+	}
+	return t.Format(rsvpTimeLayout)
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
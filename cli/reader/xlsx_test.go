@@ -0,0 +1,90 @@
+package reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// writeSignInWorkbook writes a workbook whose second sheet holds a sign-in
+// list below a merged title row, with dates stored as Excel serials.
+func writeSignInWorkbook(t *testing.T, dir string) string {
+	f := excelize.NewFile()
+	f.NewSheet("Sign In")
+	sheet := "Sign In"
+	assert.NoError(t, f.SetCellValue(sheet, "A1", "Community Hack Night"))
+	assert.NoError(t, f.MergeCell(sheet, "A1", "F1"))
+	for i, header := range []string{"Name", "User ID", "RSVP", "RSVPed on", "Joined Group on", "Legal name as it appears on your ID"} {
+		axis, _ := excelize.CoordinatesToCellName(i+1, 2)
+		assert.NoError(t, f.SetCellValue(sheet, axis, header))
+	}
+	assert.NoError(t, f.SetCellValue(sheet, "A3", "Alex Mitchell"))
+	assert.NoError(t, f.SetCellValue(sheet, "B3", "user 209174142"))
+	assert.NoError(t, f.SetCellValue(sheet, "C3", "Yes"))
+	assert.NoError(t, f.SetCellValue(sheet, "D3", time.Date(2019, time.February, 18, 17, 56, 0, 0, time.UTC)))
+	// An unformatted serial number, as left behind by a copy and paste.
+	assert.NoError(t, f.SetCellValue(sheet, "E3", 42924))
+	assert.NoError(t, f.SetCellValue(sheet, "F3", "Alex Mitchell"))
+	assert.NoError(t, f.SetCellValue(sheet, "A5", "Dubie"))
+	assert.NoError(t, f.SetCellValue(sheet, "B5", "user 192863228"))
+	assert.NoError(t, f.SetCellValue(sheet, "C5", "No"))
+	assert.NoError(t, f.SetCellValue(sheet, "F5", "The Dubester"))
+
+	path := filepath.Join(dir, "signin.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	return path
+}
+
+func TestParseAttendanceFromXLSX(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attendance-xlsx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeSignInWorkbook(t, dir)
+
+	now := time.Now()
+	event := models.NewEvent("Test Event", "1234", &now)
+	options := DefaultOptions()
+	options.Sheet = "Sign In"
+	attendance, errs := ParseFile(path, "", event, options)
+	assert.Equal(t, 0, len(errs))
+	if assert.Equal(t, 2, len(attendance)) {
+		assert.Equal(t, "user 209174142", attendance[0].Attendee().UserID())
+		assert.Equal(t, "Alex Mitchell", attendance[0].Attendee().LegalName())
+		assert.True(t, attendance[0].RSVP())
+		assert.Equal(t, time.Date(2019, time.February, 18, 17, 56, 0, 0, time.UTC), *attendance[0].RSVPTime())
+		assert.Equal(t, time.Date(2017, time.July, 8, 0, 0, 0, 0, time.UTC), *attendance[0].Attendee().JoinedDate())
+		assert.Equal(t, "The Dubester", attendance[1].Attendee().LegalName())
+		assert.False(t, attendance[1].RSVP())
+		assert.Nil(t, attendance[1].RSVPTime())
+	}
+
+	options.Sheet = "2"
+	attendance, errs = ParseAttendanceFromXLSX(path, event, options)
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, 2, len(attendance))
+
+	options.Sheet = "1"
+	_, errs = ParseAttendanceFromXLSX(path, event, options)
+	assert.Equal(t, 1, len(errs))
+
+	// Errors give the row as numbered in the sheet, counting the title
+	// and blank rows.
+	f, err := excelize.OpenFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sign In", "A6", "Bo"))
+	assert.NoError(t, f.SetCellValue("Sign In", "D6", "last Tuesday"))
+	assert.NoError(t, f.Save())
+	options.Sheet = "Sign In"
+	attendance, errs = ParseAttendanceFromXLSX(path, event, options)
+	assert.Equal(t, 2, len(attendance))
+	if assert.Equal(t, 1, len(errs)) {
+		assert.Contains(t, errs[0].Error(), "row 6")
+	}
+}
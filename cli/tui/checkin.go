@@ -0,0 +1,326 @@
+// Package tui holds the full-screen terminal interfaces.
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+const eventTimeFormat = "Mon Jan _2 3:04 PM"
+
+const searchHelp = "type to search  ↑↓ select  Enter check in  ^U undo  ^W walk-in  ^R reload  ^E close event  Esc clear/quit"
+
+type mode int
+
+const (
+	searching mode = iota
+	walkInName
+	walkInLegalName
+	confirmClose
+)
+
+// checkInScreen is the state of the door check-in screen. handleKey only
+// changes state and draw only reads it, so the key handling works without
+// a terminal.
+type checkInScreen struct {
+	session  *checkin.Session
+	location *time.Location
+	mode     mode
+	query    []rune
+	name     []rune
+	legal    []rune
+	matches  []*models.Attendance
+	selected int
+	message  string
+}
+
+// RunCheckIn runs the check-in screen until the user quits. The event's
+// time is shown in its time zone, or in location if it has none.
+func RunCheckIn(session *checkin.Session, location *time.Location) error {
+	if err := termbox.Init(); err != nil {
+		return errors.Wrap(err, "error starting terminal UI")
+	}
+	defer termbox.Close()
+	termbox.SetInputMode(termbox.InputEsc)
+
+	s := &checkInScreen{session: session, location: location}
+	s.search()
+	for {
+		s.draw()
+		ev := termbox.PollEvent()
+		switch ev.Type {
+		case termbox.EventError:
+			return errors.Wrap(ev.Err, "error reading from terminal")
+		case termbox.EventKey:
+			if s.handleKey(ev) {
+				return nil
+			}
+		}
+	}
+}
+
+func (s *checkInScreen) search() {
+	s.matches = s.session.Search(string(s.query))
+	if s.selected >= len(s.matches) {
+		s.selected = len(s.matches) - 1
+	}
+	if s.selected < 0 {
+		s.selected = 0
+	}
+}
+
+func (s *checkInScreen) selectedAttendance() *models.Attendance {
+	if len(s.matches) == 0 {
+		return nil
+	}
+	return s.matches[s.selected]
+}
+
+// editField applies a key to a text field and reports whether it did.
+func editField(field *[]rune, ev termbox.Event) bool {
+	switch {
+	case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
+		}
+	case ev.Key == termbox.KeySpace:
+		*field = append(*field, ' ')
+	case ev.Ch != 0:
+		*field = append(*field, ev.Ch)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleKey updates the screen for a key press and reports whether to quit.
+func (s *checkInScreen) handleKey(ev termbox.Event) bool {
+	if ev.Key == termbox.KeyCtrlC {
+		return true
+	}
+	if s.mode == confirmClose {
+		s.handleCloseKey(ev)
+		return false
+	}
+	if s.mode != searching {
+		s.handleWalkInKey(ev)
+		return false
+	}
+
+	switch ev.Key {
+	case termbox.KeyEsc:
+		if len(s.query) == 0 {
+			return true
+		}
+		s.query = nil
+		s.selected = 0
+	case termbox.KeyArrowUp:
+		if s.selected > 0 {
+			s.selected--
+		}
+	case termbox.KeyArrowDown:
+		if s.selected < len(s.matches)-1 {
+			s.selected++
+		}
+	case termbox.KeyEnter:
+		s.checkIn()
+		return false
+	case termbox.KeyCtrlU:
+		s.undo()
+		return false
+	case termbox.KeyCtrlW:
+		s.mode = walkInName
+		s.name = append([]rune(nil), s.query...)
+		s.legal = nil
+		s.message = "new walk-in: enter their preferred name, Esc to cancel"
+		return false
+	case termbox.KeyCtrlE:
+		s.mode = confirmClose
+		s.message = "close the event, marking everyone who RSVPed yes and isn't checked in a no-show? y to confirm"
+		return false
+	case termbox.KeyCtrlR:
+		if err := s.session.Reload(); err != nil {
+			s.message = err.Error()
+		} else {
+			s.message = "reloaded from storage"
+		}
+	default:
+		if !editField(&s.query, ev) {
+			return false
+		}
+		s.selected = 0
+	}
+	s.search()
+	return false
+}
+
+func (s *checkInScreen) handleWalkInKey(ev termbox.Event) {
+	switch ev.Key {
+	case termbox.KeyEsc:
+		s.mode = searching
+		s.message = "walk-in cancelled"
+	case termbox.KeyEnter:
+		if s.mode == walkInName {
+			s.mode = walkInLegalName
+			s.message = "new walk-in: enter their legal name as on their ID"
+			return
+		}
+		s.mode = searching
+		attendance, err := s.session.AddWalkIn(string(s.name), string(s.legal))
+		if err != nil {
+			s.message = err.Error()
+			return
+		}
+		s.message = fmt.Sprintf("checked in walk-in %s", displayName(attendance.Attendee()))
+		s.query = nil
+		s.search()
+	default:
+		if s.mode == walkInName {
+			editField(&s.name, ev)
+		} else {
+			editField(&s.legal, ev)
+		}
+	}
+}
+
+// handleCloseKey closes the event if the user confirms it with y.
+func (s *checkInScreen) handleCloseKey(ev termbox.Event) {
+	s.mode = searching
+	if ev.Ch != 'y' && ev.Ch != 'Y' {
+		s.message = "close cancelled"
+		return
+	}
+	noShows, err := s.session.Close()
+	if err != nil {
+		s.message = err.Error()
+		return
+	}
+	s.message = fmt.Sprintf("closed the event, marking %d no-shows", noShows)
+	s.search()
+}
+
+func (s *checkInScreen) checkIn() {
+	attendance := s.selectedAttendance()
+	if attendance == nil {
+		s.message = "nobody matches, ^W to add a walk-in"
+		return
+	}
+	if attendance.CameInPerson() {
+		s.message = fmt.Sprintf("%s is already checked in", displayName(attendance.Attendee()))
+		return
+	}
+	if _, err := s.session.CheckIn(attendance.Attendee().UserID()); err != nil {
+		s.message = err.Error()
+		return
+	}
+	s.message = fmt.Sprintf("checked in %s", displayName(attendance.Attendee()))
+	s.query = nil
+	s.selected = 0
+	s.search()
+}
+
+func (s *checkInScreen) undo() {
+	attendance := s.selectedAttendance()
+	if attendance == nil || !attendance.CameInPerson() {
+		return
+	}
+	if _, err := s.session.Undo(attendance.Attendee().UserID()); err != nil {
+		s.message = err.Error()
+		return
+	}
+	s.message = fmt.Sprintf("undid check-in for %s", displayName(attendance.Attendee()))
+	s.search()
+}
+
+func displayName(attendee *models.Attendee) string {
+	if attendee.PreferredName() != "" {
+		return attendee.PreferredName()
+	}
+	return attendee.LegalName()
+}
+
+// printAt writes text at x, y and returns the column after it.
+func printAt(x, y int, fg, bg termbox.Attribute, text string) int {
+	for _, r := range text {
+		termbox.SetCell(x, y, r, fg, bg)
+		x += runewidth.RuneWidth(r)
+	}
+	return x
+}
+
+func attendanceLine(attendance *models.Attendance) string {
+	marker := "[ ]"
+	if attendance.CameInPerson() {
+		marker = "[x]"
+	}
+	line := fmt.Sprintf("%s %-24s %s", marker, attendance.Attendee().PreferredName(), attendance.Attendee().LegalName())
+	switch {
+	case attendance.CheckInStatus() == models.WalkIn:
+		line += "  (walk-in)"
+	case !attendance.RSVP():
+		line += "  (RSVPed no)"
+	case attendance.Attendee().IsHost():
+		line += "  (host)"
+	}
+	return line
+}
+
+func (s *checkInScreen) draw() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	width, height := termbox.Size()
+	event := s.session.Event().Local(s.location)
+	title := "Check-in: " + event.Name()
+	if event.Time() != nil {
+		title += " — " + event.Time().Format(eventTimeFormat)
+	}
+	printAt(0, 0, termbox.AttrBold, termbox.ColorDefault, title)
+	counts := s.session.Counts()
+	printAt(0, 1, termbox.ColorDefault, termbox.ColorDefault, fmt.Sprintf(
+		"checked in %d of %d RSVPs   walk-ins %d   in the room %d",
+		counts.CheckedIn, counts.RSVPs, counts.WalkIns, counts.Present))
+
+	switch s.mode {
+	case searching:
+		x := printAt(0, 3, termbox.AttrBold, termbox.ColorDefault, "Search: ")
+		termbox.SetCursor(printAt(x, 3, termbox.ColorDefault, termbox.ColorDefault, string(s.query)), 3)
+	case walkInName, walkInLegalName:
+		x := printAt(0, 3, termbox.AttrBold, termbox.ColorDefault, "Preferred name: ")
+		end := printAt(x, 3, termbox.ColorDefault, termbox.ColorDefault, string(s.name))
+		x = printAt(0, 4, termbox.AttrBold, termbox.ColorDefault, "Legal name: ")
+		if s.mode == walkInName {
+			printAt(x, 4, termbox.ColorDefault, termbox.ColorDefault, string(s.legal))
+			termbox.SetCursor(end, 3)
+		} else {
+			termbox.SetCursor(printAt(x, 4, termbox.ColorDefault, termbox.ColorDefault, string(s.legal)), 4)
+		}
+	}
+
+	// Keep the selection on screen when there are more matches than rows.
+	top, rows := 6, height-8
+	first := 0
+	if s.selected >= rows && rows > 0 {
+		first = s.selected - rows + 1
+	}
+	for i := first; i < len(s.matches) && i-first < rows; i++ {
+		fg, bg := termbox.ColorDefault, termbox.ColorDefault
+		if i == s.selected && s.mode == searching {
+			fg, bg = termbox.AttrReverse, termbox.AttrReverse
+		}
+		line := attendanceLine(s.matches[i])
+		for runewidth.StringWidth(line) < width {
+			line += " "
+		}
+		printAt(0, top+i-first, fg, bg, line)
+	}
+
+	printAt(0, height-2, termbox.ColorDefault, termbox.ColorDefault, s.message)
+	printAt(0, height-1, termbox.ColorDefault, termbox.ColorDefault, searchHelp)
+	termbox.Flush()
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nsf/termbox-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func typeText(s *checkInScreen, text string) {
+	for _, r := range text {
+		if r == ' ' {
+			s.handleKey(termbox.Event{Type: termbox.EventKey, Key: termbox.KeySpace})
+			continue
+		}
+		s.handleKey(termbox.Event{Type: termbox.EventKey, Ch: r})
+	}
+}
+
+func press(s *checkInScreen, key termbox.Key) bool {
+	return s.handleKey(termbox.Event{Type: termbox.EventKey, Key: key})
+}
+
+func TestCheckInScreen(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	_, err := store.UpsertEvent(event)
+	assert.NoError(t, err)
+	for _, attendee := range []*models.Attendee{
+		models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, true),
+		models.NewAttendee("Dubie", "The Dubester", "user 2", nil, nil, false),
+	} {
+		_, err := store.UpsertAttendee(attendee)
+		assert.NoError(t, err)
+		_, err = store.UpsertAttendance(models.NewAttendance(attendee, event, true, nil))
+		assert.NoError(t, err)
+	}
+	session, err := checkin.NewSession(store, "event-1", "door")
+	assert.NoError(t, err)
+	s := &checkInScreen{session: session}
+	s.search()
+	assert.Len(t, s.matches, 2)
+
+	typeText(s, "dub")
+	assert.Len(t, s.matches, 1)
+	assert.False(t, press(s, termbox.KeyEnter))
+	attendance, err := store.FetchAttendance("event-1", "user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CheckedIn, attendance.CheckInStatus())
+	assert.Empty(t, s.query, "the search is cleared for the next person")
+
+	typeText(s, "Sam")
+	press(s, termbox.KeyCtrlW)
+	assert.Equal(t, walkInName, s.mode)
+	press(s, termbox.KeyEnter)
+	typeText(s, "Sam Walker")
+	press(s, termbox.KeyEnter)
+	assert.Equal(t, searching, s.mode)
+	assert.Equal(t, checkin.Counts{RSVPs: 2, CheckedIn: 1, WalkIns: 1, Present: 2}, session.Counts())
+
+	press(s, termbox.KeyCtrlE)
+	assert.Equal(t, confirmClose, s.mode)
+	press(s, termbox.KeyEsc)
+	assert.Equal(t, searching, s.mode)
+	attendance, err = store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NotCheckedIn, attendance.CheckInStatus(), "closing needs confirming")
+	press(s, termbox.KeyCtrlE)
+	typeText(s, "y")
+	attendance, err = store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NoShow, attendance.CheckInStatus())
+	assert.Empty(t, s.query)
+
+	assert.True(t, press(s, termbox.KeyEsc), "Esc with an empty search quits")
+}
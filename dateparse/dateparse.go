@@ -0,0 +1,410 @@
+// Package dateparse reads dates and times as people type them: ISO 8601 and
+// RFC 3339, US formats such as 3/14/2019 and "March 14, 2019 6:30 PM", and
+// relative forms such as "tomorrow", "next Tuesday 6:30pm" or "2 weeks ago".
+// Input that could mean more than one thing is an error rather than a guess.
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/timezone"
+)
+
+// Date is a parsed date, with or without a time of day.
+type Date struct {
+	// wall holds the date and time of day as written, in UTC.
+	wall  time.Time
+	clock bool
+	// exact is set when the input gave a UTC offset, making wall an instant
+	// in that offset rather than a wall clock time.
+	exact *time.Time
+}
+
+// HasClock reports whether a time of day was given.
+func (d Date) HasClock() bool {
+	return d.clock
+}
+
+// Wall returns the date and time of day as written, in UTC. Dates alone are
+// midnight.
+func (d Date) Wall() time.Time {
+	return d.wall
+}
+
+// In returns the instant at which clocks in location show the date, or the
+// instant itself if the input gave a UTC offset. Dates alone mean the start
+// of the day. Times that daylight saving skips or repeats in location are
+// an error.
+func (d Date) In(location *time.Location) (time.Time, error) {
+	if d.exact != nil {
+		return *d.exact, nil
+	}
+	t, err := timezone.Date(d.wall, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if timezone.Repeated(d.wall, location) {
+		_, offset := t.Zone()
+		return time.Time{}, errors.Errorf("%s happens twice in %s as clocks turn back; give its UTC offset, as in %s",
+			d.wall.Format("January 2, 2006 3:04 PM"), location, t.Format("2006-01-02T15:04:05")+formatOffset(offset))
+	}
+	return t, nil
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, seconds/3600, seconds/60%60)
+}
+
+// offsetLayouts are the ISO 8601 and RFC 3339 forms that give a UTC offset.
+var offsetLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04Z07:00",
+}
+
+var (
+	isoDatePattern  = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	isoPattern      = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})[t ](\d{2}):(\d{2})(?::(\d{2}))?$`)
+	usDatePattern   = regexp.MustCompile(`^(\d{1,2})[/-](\d{1,2})(?:[/-](\d{2}|\d{4}))?$`)
+	clockPattern    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?(am|pm|a\.m\.|p\.m\.)?$`)
+	dayOfMonthToken = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	yearToken       = regexp.MustCompile(`^\d{4}$`)
+)
+
+var months = map[string]time.Month{}
+var weekdays = map[string]time.Weekday{}
+
+func init() {
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		months[name] = m
+		months[name[:3]] = m
+	}
+	months["sept"] = time.September
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		weekdays[name] = d
+		weekdays[name[:3]] = d
+	}
+	weekdays["tues"] = time.Tuesday
+	weekdays["thur"] = time.Thursday
+	weekdays["thurs"] = time.Thursday
+}
+
+// Parse reads value. Relative forms such as "tomorrow" are resolved
+// against the date reference has in its own location.
+func Parse(value string, reference time.Time) (Date, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return Date{}, errors.New("no date given")
+	}
+	for _, layout := range offsetLayouts {
+		if t, err := time.Parse(layout, strings.ToUpper(trimmed)); err == nil {
+			return Date{wall: wallOf(t), clock: true, exact: &t}, nil
+		}
+	}
+	if strings.ToLower(trimmed) == "now" {
+		return Date{wall: wallOf(reference), clock: true, exact: &reference}, nil
+	}
+	d, err := parseFields(strings.ToLower(trimmed), reference)
+	if err != nil {
+		return Date{}, errors.Wrapf(err, "can't read %#v as a date", trimmed)
+	}
+	return d, nil
+}
+
+func wallOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func parseFields(value string, reference time.Time) (Date, error) {
+	if m := isoPattern.FindStringSubmatch(value); m != nil {
+		return isoDate(m)
+	}
+	fields := strings.Fields(strings.NewReplacer(",", " ").Replace(value))
+	var kept []string
+	for _, field := range fields {
+		if field != "at" && field != "on" {
+			kept = append(kept, field)
+		}
+	}
+	fields = kept
+
+	dateFields, clock, hasClock, err := splitClock(fields)
+	if err != nil {
+		return Date{}, err
+	}
+	date, twentyFourHour, err := parseDate(dateFields, reference)
+	if err != nil {
+		return Date{}, err
+	}
+	if !hasClock {
+		return Date{wall: date}, nil
+	}
+	if clock.ambiguous && !twentyFourHour {
+		return Date{}, errors.Errorf("%s could be morning or evening; add am or pm, or use a 24-hour time like %d:%02d", clock.text, clock.hour+12, clock.minute)
+	}
+	wall := date.Add(time.Duration(clock.hour)*time.Hour + time.Duration(clock.minute)*time.Minute + time.Duration(clock.second)*time.Second)
+	return Date{wall: wall, clock: true}, nil
+}
+
+func isoDate(m []string) (Date, error) {
+	date, err := makeDate(atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	if err != nil {
+		return Date{}, err
+	}
+	hour, minute, second := atoi(m[4]), atoi(m[5]), 0
+	if m[6] != "" {
+		second = atoi(m[6])
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return Date{}, errors.Errorf("%02d:%02d:%02d isn't a time of day", hour, minute, second)
+	}
+	wall := date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second)
+	return Date{wall: wall, clock: true}, nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// makeDate returns midnight UTC on the given date, which must exist.
+func makeDate(year, month, day int) (time.Time, error) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || t.Day() != day {
+		return time.Time{}, errors.Errorf("%d-%02d-%02d isn't a date", year, month, day)
+	}
+	return t, nil
+}
+
+type clockTime struct {
+	text                 string
+	hour, minute, second int
+	// ambiguous is set for times like 6:30 that could be morning or
+	// evening.
+	ambiguous bool
+}
+
+// splitClock takes a time of day off the end of fields, if there is one.
+func splitClock(fields []string) (rest []string, clock clockTime, ok bool, err error) {
+	n := len(fields)
+	if n == 0 {
+		return fields, clock, false, nil
+	}
+	last := fields[n-1]
+	switch last {
+	case "noon":
+		return fields[:n-1], clockTime{text: last, hour: 12}, true, nil
+	case "midnight":
+		return fields[:n-1], clockTime{text: last}, true, nil
+	}
+	text := last
+	if (last == "am" || last == "pm" || last == "a.m." || last == "p.m.") && n > 1 {
+		text = fields[n-2] + last
+		n--
+	}
+	m := clockPattern.FindStringSubmatch(text)
+	// A bare number is a day or year, not a time.
+	if m == nil || (m[2] == "" && m[4] == "") {
+		return fields, clock, false, nil
+	}
+	clock = clockTime{text: text, hour: atoi(m[1]), minute: atoi(m[2]), second: atoi(m[3])}
+	if clock.minute > 59 || clock.second > 59 {
+		return nil, clock, false, errors.Errorf("%s isn't a time of day", text)
+	}
+	switch meridiem := strings.Replace(m[4], ".", "", -1); meridiem {
+	case "":
+		if clock.hour > 23 {
+			return nil, clock, false, errors.Errorf("%s isn't a time of day", text)
+		}
+		clock.ambiguous = clock.hour >= 1 && clock.hour <= 11 && !strings.HasPrefix(m[1], "0")
+	default:
+		if clock.hour < 1 || clock.hour > 12 {
+			return nil, clock, false, errors.Errorf("%s isn't a time of day", text)
+		}
+		clock.hour %= 12
+		if meridiem == "pm" {
+			clock.hour += 12
+		}
+	}
+	return fields[:n-1], clock, true, nil
+}
+
+// parseDate reads the date part of the input, returning midnight UTC on it.
+// twentyFourHour is set for ISO dates, whose times are always 24-hour.
+func parseDate(fields []string, reference time.Time) (date time.Time, twentyFourHour bool, err error) {
+	year, month, day := reference.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if len(fields) == 0 {
+		return today, false, nil
+	}
+
+	switch fields[0] {
+	case "today", "tonight":
+		return expectEnd(today, fields[1:])
+	case "tomorrow":
+		return expectEnd(today.AddDate(0, 0, 1), fields[1:])
+	case "yesterday":
+		return expectEnd(today.AddDate(0, 0, -1), fields[1:])
+	case "next", "this", "last":
+		if len(fields) < 2 {
+			break
+		}
+		direction := map[string]int{"next": 1, "this": 0, "last": -1}[fields[0]]
+		if date, ok := offsetDate(today, "1", fields[1], direction); ok {
+			return expectEnd(date, fields[2:])
+		}
+		weekday, ok := weekdays[fields[1]]
+		if !ok {
+			return time.Time{}, false, errors.Errorf("expected a day of the week, week, month or year after %#v", fields[0])
+		}
+		return expectEnd(relativeWeekday(today, weekday, fields[0]), fields[2:])
+	case "in":
+		if len(fields) >= 3 {
+			if date, ok := offsetDate(today, fields[1], fields[2], 1); ok {
+				return expectEnd(date, fields[3:])
+			}
+		}
+	}
+	if len(fields) >= 3 && fields[2] == "ago" {
+		if date, ok := offsetDate(today, fields[0], fields[1], -1); ok {
+			return expectEnd(date, fields[3:])
+		}
+	}
+
+	weekday, hasWeekday := weekdays[fields[0]]
+	if hasWeekday {
+		fields = fields[1:]
+		if len(fields) == 0 {
+			return relativeWeekday(today, weekday, "this"), false, nil
+		}
+	}
+	date, twentyFourHour, err = absoluteDate(fields)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if hasWeekday && date.Weekday() != weekday {
+		return time.Time{}, false, errors.Errorf("%s is a %s, not a %s", date.Format("January 2, 2006"), date.Weekday(), weekday)
+	}
+	return date, twentyFourHour, nil
+}
+
+func expectEnd(date time.Time, rest []string) (time.Time, bool, error) {
+	if len(rest) > 0 {
+		return time.Time{}, false, errors.Errorf("unexpected %#v", strings.Join(rest, " "))
+	}
+	return date, false, nil
+}
+
+// relativeWeekday finds weekday relative to today. "this" means the first
+// one from today on, "next" the first one after today and "last" the latest
+// one before today.
+func relativeWeekday(today time.Time, weekday time.Weekday, which string) time.Time {
+	if which == "last" {
+		days := int(today.Weekday()-weekday+7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, -days)
+	}
+	days := int(weekday-today.Weekday()+7) % 7
+	if days == 0 && which == "next" {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// offsetDate reads forms like "3 days" and "a week", moving today by them
+// in direction.
+func offsetDate(today time.Time, count, unit string, direction int) (time.Time, bool) {
+	n, err := strconv.Atoi(count)
+	if count == "a" || count == "an" {
+		n, err = 1, nil
+	}
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	n *= direction
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return today.AddDate(0, 0, n), true
+	case "week":
+		return today.AddDate(0, 0, 7*n), true
+	case "month":
+		return today.AddDate(0, n, 0), true
+	case "year":
+		return today.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// absoluteDate reads ISO dates, US month/day/year dates and dates with the
+// month's name, which need a year.
+func absoluteDate(fields []string) (time.Time, bool, error) {
+	if len(fields) == 1 {
+		if m := isoDatePattern.FindStringSubmatch(fields[0]); m != nil {
+			date, err := makeDate(atoi(m[1]), atoi(m[2]), atoi(m[3]))
+			return date, true, err
+		}
+		if m := usDatePattern.FindStringSubmatch(fields[0]); m != nil {
+			date, err := usDate(fields[0], atoi(m[1]), atoi(m[2]), m[3])
+			return date, false, err
+		}
+	}
+
+	var month time.Month
+	var day, year int
+	var monthOK, dayOK, yearOK bool
+	for _, field := range fields {
+		if m, ok := months[strings.TrimSuffix(field, ".")]; ok && !monthOK {
+			month, monthOK = m, true
+			continue
+		}
+		if m := dayOfMonthToken.FindStringSubmatch(field); m != nil && !dayOK {
+			day, dayOK = atoi(m[1]), true
+			continue
+		}
+		if yearToken.MatchString(field) && !yearOK {
+			year, yearOK = atoi(field), true
+			continue
+		}
+		return time.Time{}, false, errors.Errorf("unexpected %#v", field)
+	}
+	if !monthOK || !dayOK {
+		return time.Time{}, false, errors.New("expected a date such as 2019-03-14, 3/14/2019, March 14, 2019 or next Tuesday")
+	}
+	if !yearOK {
+		return time.Time{}, false, errors.Errorf("give the year, as in %s %d, 2019", month, day)
+	}
+	date, err := makeDate(year, int(month), day)
+	return date, false, err
+}
+
+// usDate reads month/day/year dates. Two-digit years are in the 2000s.
+// Dates whose first number can't be a month are rejected rather than read
+// day first, since day-first dates that could be either would be misread.
+func usDate(text string, month, day int, year string) (time.Time, error) {
+	if year == "" {
+		return time.Time{}, errors.Errorf("give the year, as in %s/2019", text)
+	}
+	if month > 12 && day <= 12 {
+		return time.Time{}, errors.Errorf("%s isn't month/day/year; write dates like 3/14/2019 or 2019-03-14", text)
+	}
+	y := atoi(year)
+	if len(year) == 2 {
+		y += 2000
+	}
+	return makeDate(y, month, day)
+}
@@ -0,0 +1,148 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestParse(t *testing.T) {
+	losAngeles := mustLoad(t, "America/Los_Angeles")
+	// Thursday, March 14, 2019, at noon in Los Angeles.
+	reference := time.Date(2019, time.March, 14, 12, 0, 0, 0, losAngeles)
+	wall := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2019, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	for _, test := range []struct {
+		value    string
+		expected time.Time
+		clock    bool
+	}{
+		// ISO 8601.
+		{"2019-03-14", wall(time.March, 14, 0, 0), false},
+		{"2019-03-14T18:30", wall(time.March, 14, 18, 30), true},
+		{"2019-03-14 18:30:00", wall(time.March, 14, 18, 30), true},
+		{"2019-03-14 6:30", wall(time.March, 14, 6, 30), true},
+		{"2019-03-14 6:30 pm", wall(time.March, 14, 18, 30), true},
+		// US formats.
+		{"3/14/2019", wall(time.March, 14, 0, 0), false},
+		{"03/14/19 6:30 PM", wall(time.March, 14, 18, 30), true},
+		{"3-14-2019 18:30", wall(time.March, 14, 18, 30), true},
+		{"March 14, 2019", wall(time.March, 14, 0, 0), false},
+		{"March 14, 2019 6:30PM", wall(time.March, 14, 18, 30), true},
+		{"Thursday, March 14, 2019 at 6:30 p.m.", wall(time.March, 14, 18, 30), true},
+		{"Mar 14th 2019 6pm", wall(time.March, 14, 18, 0), true},
+		{"14 March 2019 noon", wall(time.March, 14, 12, 0), true},
+		{"Sept. 3, 2019 12am", wall(time.September, 3, 0, 0), true},
+		// Relative to the reference.
+		{"today", wall(time.March, 14, 0, 0), false},
+		{"6:30pm", wall(time.March, 14, 18, 30), true},
+		{"tonight at 7pm", wall(time.March, 14, 19, 0), true},
+		{"tomorrow 09:00", wall(time.March, 15, 9, 0), true},
+		{"yesterday", wall(time.March, 13, 0, 0), false},
+		{"Tuesday", wall(time.March, 19, 0, 0), false},
+		{"thursday", wall(time.March, 14, 0, 0), false},
+		{"this Thursday 6:30pm", wall(time.March, 14, 18, 30), true},
+		{"next Tuesday 6:30pm", wall(time.March, 19, 18, 30), true},
+		{"next thursday", wall(time.March, 21, 0, 0), false},
+		{"last Thursday", wall(time.March, 7, 0, 0), false},
+		{"last fri", wall(time.March, 8, 0, 0), false},
+		{"in 3 days", wall(time.March, 17, 0, 0), false},
+		{"in a week at midnight", wall(time.March, 21, 0, 0), true},
+		{"2 weeks ago", wall(time.February, 28, 0, 0), false},
+		{"in 1 month", wall(time.April, 14, 0, 0), false},
+		{"last week", wall(time.March, 7, 0, 0), false},
+		{"next month at noon", wall(time.April, 14, 12, 0), true},
+	} {
+		d, err := Parse(test.value, reference)
+		if assert.NoError(t, err, test.value) {
+			assert.Equal(t, test.expected, d.Wall(), test.value)
+			assert.Equal(t, test.clock, d.HasClock(), test.value)
+		}
+	}
+}
+
+func TestParseOffsets(t *testing.T) {
+	reference := time.Date(2019, time.March, 14, 12, 0, 0, 0, time.UTC)
+	expected := time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC)
+	for _, value := range []string{"2019-03-14T18:30:00-07:00", "2019-03-15T01:30:00Z", "2019-03-14 18:30-07:00", "2019-03-15t01:30z"} {
+		d, err := Parse(value, reference)
+		if assert.NoError(t, err, value) {
+			// The offset given wins over the location.
+			actual, err := d.In(mustLoad(t, "Asia/Tokyo"))
+			assert.NoError(t, err, value)
+			assert.True(t, expected.Equal(actual), value)
+		}
+	}
+
+	d, err := Parse("now", reference)
+	assert.NoError(t, err)
+	actual, err := d.In(time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, reference, actual)
+}
+
+func TestIn(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	reference := time.Date(2019, time.March, 14, 12, 0, 0, 0, newYork)
+	for _, test := range []struct {
+		value    string
+		expected time.Time
+	}{
+		// Either side of clocks springing forward and falling back.
+		{"March 9, 2019 6:30pm", time.Date(2019, time.March, 9, 23, 30, 0, 0, time.UTC)},
+		{"March 10, 2019 6:30pm", time.Date(2019, time.March, 10, 22, 30, 0, 0, time.UTC)},
+		{"2019-11-02", time.Date(2019, time.November, 2, 4, 0, 0, 0, time.UTC)},
+		{"2019-11-04", time.Date(2019, time.November, 4, 5, 0, 0, 0, time.UTC)},
+	} {
+		d, err := Parse(test.value, reference)
+		assert.NoError(t, err, test.value)
+		actual, err := d.In(newYork)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, actual.UTC(), test.value)
+		assert.Equal(t, newYork, actual.Location(), test.value)
+	}
+
+	d, err := Parse("March 10, 2019 2:30am", reference)
+	assert.NoError(t, err)
+	_, err = d.In(newYork)
+	assert.EqualError(t, err, "March 10, 2019 2:30 AM doesn't happen in America/New_York: clocks skip it for daylight saving time")
+	d, err = Parse("November 3, 2019 1:30am", reference)
+	assert.NoError(t, err)
+	_, err = d.In(newYork)
+	assert.EqualError(t, err, "November 3, 2019 1:30 AM happens twice in America/New_York as clocks turn back; give its UTC offset, as in 2019-11-03T01:30:00-04:00")
+	// It's fine where clocks don't change.
+	actual, err := d.In(time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.November, 3, 1, 30, 0, 0, time.UTC), actual)
+}
+
+func TestParseErrors(t *testing.T) {
+	reference := time.Date(2019, time.March, 14, 12, 0, 0, 0, time.UTC)
+	for value, message := range map[string]string{
+		"":                      "no date given",
+		"next Tuesday 6:30":     `can't read "next Tuesday 6:30" as a date: 6:30 could be morning or evening; add am or pm, or use a 24-hour time like 18:30`,
+		"14/03/2019":            `can't read "14/03/2019" as a date: 14/03/2019 isn't month/day/year; write dates like 3/14/2019 or 2019-03-14`,
+		"March 14":              `can't read "March 14" as a date: give the year, as in March 14, 2019`,
+		"3/14":                  `can't read "3/14" as a date: give the year, as in 3/14/2019`,
+		"Friday, March 14 2019": `can't read "Friday, March 14 2019" as a date: March 14, 2019 is a Thursday, not a Friday`,
+		"February 30, 2019":     `can't read "February 30, 2019" as a date: 2019-02-30 isn't a date`,
+		"next March":            `can't read "next March" as a date: expected a day of the week, week, month or year after "next"`,
+		"tomorrow tuesday":      `can't read "tomorrow tuesday" as a date: unexpected "tuesday"`,
+		"13:30pm":               `can't read "13:30pm" as a date: 13:30pm isn't a time of day`,
+		"soon":                  `can't read "soon" as a date: unexpected "soon"`,
+		"2019-03-14T25:00":      `can't read "2019-03-14T25:00" as a date: 25:00:00 isn't a time of day`,
+	} {
+		_, err := Parse(value, reference)
+		assert.EqualError(t, err, message, value)
+	}
+}
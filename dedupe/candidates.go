@@ -0,0 +1,92 @@
+package dedupe
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// DefaultMinScore is the lowest score FindCandidates reports by default. A
+// matching legal name is enough to reach it; a matching preferred name alone
+// isn't.
+const DefaultMinScore = 0.5
+
+// Candidate is a pair of attendees that are probably the same person.
+// Keep is the one suggested to survive a merge: an attendee with a user ID
+// from an RSVP system is preferred over a walk-in.
+type Candidate struct {
+	Keep    *models.Attendee
+	Drop    *models.Attendee
+	Score   float64
+	Reasons []string
+}
+
+// FindCandidates returns every pair of attendees scoring at least minScore,
+// best first. Only attendees sharing a normalized field are compared, so
+// large groups don't need every pair scored.
+func FindCandidates(attendees []*models.Attendee, minScore float64) []*Candidate {
+	type bucketKey struct {
+		field int
+		value string
+	}
+	buckets := map[bucketKey][]int{}
+	for i, attendee := range attendees {
+		for f, field := range fields {
+			if value := field.key(attendee); value != "" {
+				key := bucketKey{f, value}
+				buckets[key] = append(buckets[key], i)
+			}
+		}
+	}
+
+	compared := map[[2]int]bool{}
+	var candidates []*Candidate
+	for _, bucket := range buckets {
+		for x := 0; x < len(bucket); x++ {
+			for y := x + 1; y < len(bucket); y++ {
+				pair := [2]int{bucket[x], bucket[y]}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+				a, b := attendees[pair[0]], attendees[pair[1]]
+				score, reasons := Score(a, b)
+				if score < minScore {
+					continue
+				}
+				keep, drop := survivor(a, b)
+				candidates = append(candidates, &Candidate{Keep: keep, Drop: drop, Score: score, Reasons: reasons})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.Score != cj.Score {
+			return ci.Score > cj.Score
+		}
+		if ci.Keep.UserID() != cj.Keep.UserID() {
+			return ci.Keep.UserID() < cj.Keep.UserID()
+		}
+		return ci.Drop.UserID() < cj.Drop.UserID()
+	})
+	return candidates
+}
+
+// survivor orders a pair as the attendee to keep and the one to drop.
+func survivor(a, b *models.Attendee) (keep, drop *models.Attendee) {
+	aWalkIn := strings.HasPrefix(a.UserID(), checkin.WalkInUserIDPrefix)
+	bWalkIn := strings.HasPrefix(b.UserID(), checkin.WalkInUserIDPrefix)
+	if aWalkIn != bWalkIn {
+		if aWalkIn {
+			return b, a
+		}
+		return a, b
+	}
+	if b.UserID() < a.UserID() {
+		return b, a
+	}
+	return a, b
+}
@@ -0,0 +1,31 @@
+package dedupe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestFindCandidates(t *testing.T) {
+	attendees := []*models.Attendee{
+		models.NewAttendee("Alex", "", checkin.WalkInUserIDPrefix+"1", nil, nil, false).WithEmail("alex@example.com"),
+		models.NewAttendee("Alex", "Alex Mitchell", "user 2", nil, nil, false).WithEmail("alex@example.com"),
+		models.NewAttendee("Alex", "Alex Smith", "user 3", nil, nil, false),
+		models.NewAttendee("Sam", "Alex Smith", "user 4", nil, nil, false),
+	}
+
+	candidates := FindCandidates(attendees, DefaultMinScore)
+	if assert.Len(t, candidates, 2) {
+		assert.Equal(t, "user 2", candidates[0].Keep.UserID())
+		assert.Equal(t, checkin.WalkInUserIDPrefix+"1", candidates[0].Drop.UserID())
+		assert.Equal(t, []string{"same email", "same preferred name"}, candidates[0].Reasons)
+		assert.Equal(t, "user 3", candidates[1].Keep.UserID())
+		assert.Equal(t, "user 4", candidates[1].Drop.UserID())
+	}
+
+	// Sharing only a preferred name isn't enough by default.
+	assert.Len(t, FindCandidates(attendees, 0.3), 4)
+}
@@ -0,0 +1,148 @@
+package dedupe
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+// MergeSummary says what a merge changed.
+type MergeSummary struct {
+	// Attendee is the surviving attendee, with any details it lacked filled
+	// in from the dropped one.
+	Attendee *models.Attendee
+	// Moved counts attendances moved over from the dropped attendee, and
+	// Combined those folded into an attendance the survivor already had
+	// for the same event.
+	Moved    int
+	Combined int
+	// Aliases are the user IDs that now resolve to the survivor because of
+	// this merge: the dropped attendee's and any merged into it earlier.
+	Aliases []string
+}
+
+// Merge folds the attendee dropID into keepID in one transaction. Every
+// attendance of the dropped attendee moves to the survivor, the dropped
+// attendee is deleted, and its user ID becomes an alias of the survivor so
+// that later imports using it are attributed to the survivor.
+func Merge(store storage.Store, keepID, dropID string) (*MergeSummary, error) {
+	if keepID == dropID {
+		return nil, errors.Errorf("can't merge attendee %#v into itself", keepID)
+	}
+	summary := &MergeSummary{}
+	err := store.WithTx(func(tx storage.Store) error {
+		keep, err := tx.FetchAttendee(keepID)
+		if err != nil {
+			return err
+		}
+		drop, err := tx.FetchAttendee(dropID)
+		if err != nil {
+			return err
+		}
+		summary.Attendee = mergeAttendees(keep, drop)
+		if _, err := tx.UpsertAttendee(summary.Attendee); err != nil {
+			return err
+		}
+
+		attendances, err := tx.GetAttendancesForAttendee(dropID)
+		if err != nil {
+			return err
+		}
+		for _, attendance := range attendances {
+			eventID := attendance.Event().ID()
+			moved := models.NewAttendance(summary.Attendee, attendance.Event(), attendance.RSVP(), attendance.RSVPTime()).
+				WithCheckIn(attendance.CheckInStatus(), attendance.CheckInTime(), attendance.CheckedInBy()).
+				WithCancelled(attendance.Cancelled())
+			existing, err := tx.FetchAttendance(eventID, keepID)
+			switch {
+			case err == nil:
+				moved = mergeAttendances(existing, moved)
+				summary.Combined++
+			case errors.Cause(err) == storage.ErrNoAttendanceEntry:
+				summary.Moved++
+			default:
+				return err
+			}
+			if _, err := tx.UpsertAttendance(moved); err != nil {
+				return err
+			}
+			if err := tx.DeleteAttendance(eventID, dropID); err != nil {
+				return err
+			}
+		}
+
+		aliases, err := tx.GetAliases(dropID)
+		if err != nil {
+			return err
+		}
+		summary.Aliases = append([]string{dropID}, aliases...)
+		for _, alias := range summary.Aliases {
+			if _, err := tx.UpsertAlias(alias, keepID); err != nil {
+				return err
+			}
+		}
+		return tx.DeleteAttendee(dropID)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error merging attendee %#v into %#v", dropID, keepID)
+	}
+	return summary, nil
+}
+
+// mergeAttendees keeps keep's details, filling in any that are blank from
+// drop. The earlier joined date wins, and either being a host is enough.
+func mergeAttendees(keep, drop *models.Attendee) *models.Attendee {
+	legalName := keep.LegalName()
+	if legalName == "" {
+		legalName = drop.LegalName()
+	}
+	profileURL := keep.ProfileURL()
+	if profileURL == nil || profileURL.String() == "" {
+		profileURL = drop.ProfileURL()
+	}
+	joined := keep.JoinedDate()
+	if dropJoined := drop.JoinedDate(); dropJoined != nil && (joined == nil || dropJoined.Before(*joined)) {
+		joined = dropJoined
+	}
+	email := keep.Email()
+	if email == "" {
+		email = drop.Email()
+	}
+	return models.NewAttendee(keep.PreferredName(), legalName, keep.UserID(), profileURL, joined, keep.IsHost() || drop.IsHost()).WithEmail(email)
+}
+
+// mergeAttendances combines two records of the same person at the same
+// event. A yes RSVP from either counts, and coming in person under either
+// record beats a no-show, which beats no check-in at all.
+func mergeAttendances(keep, drop *models.Attendance) *models.Attendance {
+	rsvpSource := keep
+	if !keep.RSVP() && drop.RSVP() {
+		rsvpSource = drop
+	}
+	checkInSource := keep
+	if checkInRank(drop) > checkInRank(keep) {
+		checkInSource = drop
+	}
+	status := checkInSource.CheckInStatus()
+	if checkInSource.CameInPerson() {
+		// Whether they came as a walk-in depends on the merged RSVP.
+		status = models.CheckedIn
+		if !rsvpSource.RSVP() {
+			status = models.WalkIn
+		}
+	}
+	return models.NewAttendance(keep.Attendee(), keep.Event(), rsvpSource.RSVP(), rsvpSource.RSVPTime()).
+		WithCheckIn(status, checkInSource.CheckInTime(), checkInSource.CheckedInBy()).
+		WithCancelled(rsvpSource.Cancelled())
+}
+
+func checkInRank(a *models.Attendance) int {
+	switch {
+	case a.CameInPerson():
+		return 2
+	case a.CheckInStatus() == models.NoShow:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package dedupe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func TestMerge(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	first := time.Date(2019, time.February, 14, 18, 30, 0, 0, time.UTC)
+	second := first.AddDate(0, 1, 0)
+	joined := time.Date(2017, time.July, 8, 0, 0, 0, 0, time.UTC)
+	keep := models.NewAttendee("Alex", "", "user 2", nil, nil, false)
+	drop := models.NewAttendee("Alex M", "Alex Mitchell", "user 1", nil, &joined, true).WithEmail("alex@example.com")
+	events := []*models.Event{models.NewEvent("Hack Night", "event-1", &first), models.NewEvent("Hack Night", "event-2", &second)}
+	for _, e := range events {
+		_, err := store.UpsertEvent(e)
+		assert.NoError(t, err)
+	}
+	for _, a := range []*models.Attendee{keep, drop} {
+		_, err := store.UpsertAttendee(a)
+		assert.NoError(t, err)
+	}
+	for _, a := range []*models.Attendance{
+		models.NewAttendance(drop, events[0], true, nil).WithCheckIn(models.NoShow, nil, "door"),
+		models.NewAttendance(keep, events[0], false, nil).WithCheckIn(models.WalkIn, &first, "door"),
+		models.NewAttendance(drop, events[1], true, nil),
+	} {
+		_, err := store.UpsertAttendance(a)
+		assert.NoError(t, err)
+	}
+	_, err := store.UpsertAlias("user 0", "user 1")
+	assert.NoError(t, err)
+
+	summary, err := Merge(store, "user 2", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Moved)
+	assert.Equal(t, 1, summary.Combined)
+	assert.Equal(t, []string{"user 1", "user 0"}, summary.Aliases)
+
+	survivor, err := store.FetchAttendee("user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alex", survivor.PreferredName())
+	assert.Equal(t, "Alex Mitchell", survivor.LegalName())
+	assert.Equal(t, "alex@example.com", survivor.Email())
+	assert.Equal(t, joined, *survivor.JoinedDate())
+	assert.True(t, survivor.IsHost())
+
+	_, err = store.FetchAttendee("user 1")
+	assert.Equal(t, storage.ErrNoEntryWithUserID, errors.Cause(err))
+	attendances, err := store.GetAttendancesForAttendee("user 2")
+	assert.NoError(t, err)
+	if assert.Len(t, attendances, 2) {
+		// RSVPed yes under one account and walked in under the other.
+		assert.True(t, attendances[0].RSVP())
+		assert.Equal(t, models.CheckedIn, attendances[0].CheckInStatus())
+		assert.Equal(t, first, *attendances[0].CheckInTime())
+		assert.Equal(t, "event-2", attendances[1].Event().ID())
+	}
+
+	for _, alias := range []string{"user 0", "user 1"} {
+		resolved, err := store.ResolveUserID(alias)
+		assert.NoError(t, err)
+		assert.Equal(t, "user 2", resolved)
+	}
+}
+
+func TestMergeRequiresBothAttendees(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	_, err := store.UpsertAttendee(models.NewAttendee("Alex", "", "user 1", nil, nil, false))
+	assert.NoError(t, err)
+
+	_, err = Merge(store, "user 1", "user 2")
+	assert.Equal(t, storage.ErrNoEntryWithUserID, errors.Cause(err))
+	_, err = Merge(store, "user 1", "user 1")
+	assert.Error(t, err)
+	resolved, err := store.ResolveUserID("user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "user 2", resolved)
+}
@@ -0,0 +1,52 @@
+// Package dedupe finds attendees that are probably the same person and
+// merges them.
+package dedupe
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// Fields two attendees can match on, with how likely a match on that field
+// alone makes it that they are the same person.
+var fields = []struct {
+	reason string
+	weight float64
+	key    func(a *models.Attendee) string
+}{
+	{"same email", 0.95, func(a *models.Attendee) string { return checkin.NormalizeEmail(a.Email()) }},
+	{"same profile URL", 0.9, func(a *models.Attendee) string { return normalizeProfileURL(a.ProfileURL()) }},
+	{"same legal name", 0.7, func(a *models.Attendee) string { return checkin.NormalizeName(a.LegalName()) }},
+	{"same preferred name", 0.4, func(a *models.Attendee) string { return checkin.NormalizeName(a.PreferredName()) }},
+}
+
+// Score rates how likely a and b are to be the same person, from 0 to 1,
+// and says which fields matched. Each matching field is treated as
+// independent evidence, so matches on several fields score higher than a
+// match on any one of them. Blank fields never match.
+func Score(a, b *models.Attendee) (float64, []string) {
+	var reasons []string
+	unlikely := 1.0
+	for _, field := range fields {
+		key := field.key(a)
+		if key == "" || key != field.key(b) {
+			continue
+		}
+		reasons = append(reasons, field.reason)
+		unlikely *= 1 - field.weight
+	}
+	return 1 - unlikely, reasons
+}
+
+// normalizeProfileURL reduces a profile URL to its host and path, ignoring
+// scheme, case, a "www." prefix and trailing slashes.
+func normalizeProfileURL(u *url.URL) string {
+	if u == nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	return host + strings.TrimRight(strings.ToLower(u.Path), "/")
+}
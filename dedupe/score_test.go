@@ -0,0 +1,32 @@
+package dedupe
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func profile(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	assert.NoError(t, err)
+	return u
+}
+
+func TestScore(t *testing.T) {
+	alex := models.NewAttendee("Alex", "Alex Mitchell", "user 1", profile(t, "https://www.meetup.com/members/1/"), nil, false).WithEmail("alex@example.com")
+
+	score, reasons := Score(alex, models.NewAttendee("alex", "ALEX MITCHELL", "user 2", nil, nil, false))
+	assert.InDelta(t, 1-0.3*0.6, score, 1e-9)
+	assert.Equal(t, []string{"same legal name", "same preferred name"}, reasons)
+
+	score, reasons = Score(alex, models.NewAttendee("A", "", "user 3", profile(t, "http://meetup.com/members/1"), nil, false).WithEmail("Alex+rsvp@example.com"))
+	assert.InDelta(t, 1-0.05*0.1, score, 1e-9)
+	assert.Equal(t, []string{"same email", "same profile URL"}, reasons)
+
+	score, reasons = Score(models.NewAttendee("", "", "user 4", nil, nil, false), models.NewAttendee("", "", "user 5", nil, nil, false))
+	assert.Zero(t, score)
+	assert.Empty(t, reasons)
+}
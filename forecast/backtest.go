@@ -0,0 +1,112 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alexthemitchell/community-attendance/analytics"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// BacktestEvent compares one past event's forecast with what happened.
+type BacktestEvent struct {
+	EventName string    `json:"event_name"`
+	EventTime time.Time `json:"event_time"`
+	Forecast  *Forecast `json:"forecast"`
+	Actual    int       `json:"actual"`
+	// Covered says whether Actual fell inside the forecast interval.
+	Covered bool `json:"covered"`
+}
+
+// Error is the forecast minus the actual turnout.
+func (e *BacktestEvent) Error() float64 {
+	return e.Forecast.Expected - float64(e.Actual)
+}
+
+// BacktestReport summarizes how a model would have done on past events.
+type BacktestReport struct {
+	Model  string           `json:"model"`
+	Level  float64          `json:"level"`
+	Events []*BacktestEvent `json:"events"`
+	// MAE is the mean absolute error in people.
+	MAE float64 `json:"mae"`
+	// MAPE is the mean absolute error as a share of actual turnout, over
+	// the events anyone turned up to.
+	MAPE float64 `json:"mape"`
+	// Bias is the mean error; positive means the model over-forecasts.
+	Bias float64 `json:"bias"`
+	// Coverage is the share of events whose actual turnout fell inside
+	// the interval. A calibrated model's coverage is close to Level.
+	Coverage float64 `json:"coverage"`
+	// Skipped counts past events left out because nobody's check-in was
+	// recorded, so their actual turnout isn't known.
+	Skipped int `json:"skipped"`
+}
+
+// Backtest forecasts every event that happened before now using only the
+// history available before it started, and compares the forecasts with
+// the recorded turnout. Events without recorded check-ins are skipped:
+// their turnout would be inferred from the RSVPs being forecast from.
+func Backtest(events []*models.Event, attendances []*models.Attendance, model Model, level float64, now time.Time) (*BacktestReport, error) {
+	checkInsTaken := analytics.CheckInsTaken(attendances)
+	byEvent := map[string][]*models.Attendance{}
+	for _, attendance := range attendances {
+		eventID := attendance.Event().ID()
+		byEvent[eventID] = append(byEvent[eventID], attendance)
+	}
+
+	report := &BacktestReport{Model: model.Name(), Level: level}
+	var absoluteErrors, percentageErrors, errorSum float64
+	var percentageCount, covered int
+	for _, event := range events {
+		eventTime := event.Time()
+		forEvent := byEvent[event.ID()]
+		if eventTime == nil || !eventTime.Before(now) || len(forEvent) == 0 {
+			continue
+		}
+		if !checkInsTaken[event.ID()] {
+			report.Skipped++
+			continue
+		}
+		f, err := Predict(event, forEvent, NewHistory(attendances, *eventTime), model, level)
+		if err != nil {
+			return nil, err
+		}
+		result := &BacktestEvent{
+			EventName: event.Name(),
+			EventTime: *eventTime,
+			Forecast:  f,
+		}
+		for _, attendance := range forEvent {
+			if analytics.Attended(attendance, checkInsTaken) {
+				result.Actual++
+			}
+		}
+		result.Covered = result.Actual >= f.Low && result.Actual <= f.High
+		report.Events = append(report.Events, result)
+
+		errorSum += result.Error()
+		absoluteErrors += math.Abs(result.Error())
+		if result.Actual > 0 {
+			percentageErrors += math.Abs(result.Error()) / float64(result.Actual)
+			percentageCount++
+		}
+		if result.Covered {
+			covered++
+		}
+	}
+	sort.SliceStable(report.Events, func(i, j int) bool {
+		return report.Events[i].EventTime.Before(report.Events[j].EventTime)
+	})
+
+	if n := float64(len(report.Events)); n > 0 {
+		report.MAE = absoluteErrors / n
+		report.Bias = errorSum / n
+		report.Coverage = float64(covered) / n
+	}
+	if percentageCount > 0 {
+		report.MAPE = percentageErrors / float64(percentageCount)
+	}
+	return report, nil
+}
@@ -0,0 +1,51 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestBacktest(t *testing.T) {
+	now := time.Date(2019, time.June, 1, 12, 0, 0, 0, time.UTC)
+	first, second, upcoming := now.AddDate(0, 0, -14), now.AddDate(0, 0, -7), now.AddDate(0, 0, 7)
+	events := []*models.Event{
+		models.NewEvent("Second", "2", &second),
+		models.NewEvent("First", "1", &first),
+		models.NewEvent("Upcoming", "3", &upcoming),
+		models.NewEvent("Empty", "4", &first),
+		models.NewEvent("Unchecked", "5", &second),
+	}
+	var attendances []*models.Attendance
+	for _, attendance := range append(rsvps(events[1], 4), rsvps(events[0], 2)...) {
+		attendances = append(attendances, attendance.WithCheckIn(models.CheckedIn, nil, "door"))
+	}
+	attendances = append(attendances, rsvps(events[2], 5)...)
+	attendances = append(attendances, rsvps(events[4], 3)...)
+
+	report, err := Backtest(events, attendances, fixedModel(0.5), 0.9, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "fixed", report.Model)
+	if assert.Len(t, report.Events, 2) {
+		assert.Equal(t, "First", report.Events[0].EventName)
+		assert.Equal(t, 4, report.Events[0].Actual)
+		assert.InDelta(t, -2, report.Events[0].Error(), 1e-9)
+	}
+	assert.InDelta(t, 1.5, report.MAE, 1e-9)
+	assert.InDelta(t, -1.5, report.Bias, 1e-9)
+	assert.InDelta(t, 0.5, report.MAPE, 1e-9)
+	assert.Equal(t, 1, report.Skipped)
+}
+
+func TestModelByName(t *testing.T) {
+	for _, name := range ModelNames() {
+		model, err := ModelByName(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, model.Name())
+	}
+	_, err := ModelByName("crystal-ball")
+	assert.Error(t, err)
+}
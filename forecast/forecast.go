@@ -0,0 +1,87 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// DefaultLevel is the default coverage of a forecast's interval.
+const DefaultLevel = 0.9
+
+// GuestForecast is one RSVP's chance of showing up.
+type GuestForecast struct {
+	UserID        string  `json:"user_id"`
+	PreferredName string  `json:"preferred_name"`
+	Probability   float64 `json:"probability"`
+}
+
+// Forecast is the expected turnout for an event with an interval around it.
+type Forecast struct {
+	EventID  string  `json:"event_id"`
+	Model    string  `json:"model"`
+	RSVPs    int     `json:"rsvps"`
+	Expected float64 `json:"expected"`
+	Low      int     `json:"low"`
+	High     int     `json:"high"`
+	// Level is the share of outcomes the Low to High interval should
+	// cover.
+	Level  float64         `json:"level"`
+	Guests []GuestForecast `json:"guests,omitempty"`
+}
+
+// zScore is the two-sided standard normal quantile for level, found by
+// bisecting the error function.
+func zScore(level float64) float64 {
+	low, high := 0.0, 10.0
+	for i := 0; i < 60; i++ {
+		mid := (low + high) / 2
+		if math.Erf(mid/math.Sqrt2) < level {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// Predict forecasts turnout from the event's attendances. Each yes RSVP is
+// treated as an independent chance of showing up, so the expected turnout
+// is the sum of the probabilities and the interval is a normal
+// approximation of their spread, clamped to the number of RSVPs.
+func Predict(event *models.Event, attendances []*models.Attendance, history *History, model Model, level float64) (*Forecast, error) {
+	if level <= 0 || level >= 1 {
+		return nil, errors.Errorf("interval level %v must be between 0 and 1", level)
+	}
+	f := &Forecast{
+		EventID: event.ID(),
+		Model:   model.Name(),
+		Level:   level,
+	}
+	var variance float64
+	for _, attendance := range attendances {
+		if !attendance.RSVP() {
+			continue
+		}
+		p := model.ShowProbability(attendance, history)
+		f.RSVPs++
+		f.Expected += p
+		variance += p * (1 - p)
+		f.Guests = append(f.Guests, GuestForecast{
+			UserID:        attendance.Attendee().UserID(),
+			PreferredName: attendance.Attendee().PreferredName(),
+			Probability:   p,
+		})
+	}
+	sort.SliceStable(f.Guests, func(i, j int) bool {
+		return f.Guests[i].Probability > f.Guests[j].Probability
+	})
+
+	margin := zScore(level) * math.Sqrt(variance)
+	f.Low = int(math.Max(0, math.Floor(f.Expected-margin)))
+	f.High = int(math.Min(float64(f.RSVPs), math.Ceil(f.Expected+margin)))
+	return f, nil
+}
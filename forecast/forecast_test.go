@@ -0,0 +1,63 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// fixedModel gives every RSVP the same probability.
+type fixedModel float64
+
+func (fixedModel) Name() string { return "fixed" }
+
+func (m fixedModel) ShowProbability(*models.Attendance, *History) float64 {
+	return float64(m)
+}
+
+func rsvps(event *models.Event, count int) []*models.Attendance {
+	var attendances []*models.Attendance
+	for i := 0; i < count; i++ {
+		attendee := models.NewAttendee("Guest", "", string(rune('a'+i)), nil, nil, false)
+		attendances = append(attendances, models.NewAttendance(attendee, event, true, nil))
+	}
+	return attendances
+}
+
+func TestPredict(t *testing.T) {
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	attendances := rsvps(event, 20)
+	attendances = append(attendances, models.NewAttendance(models.NewAttendee("No", "", "no", nil, nil, false), event, false, nil))
+
+	f, err := Predict(event, attendances, NewHistory(nil, eventTime), fixedModel(0.5), 0.9)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, f.RSVPs)
+	assert.InDelta(t, 10, f.Expected, 1e-9)
+	// 1.645 * sqrt(20 * 0.25) is about 3.7.
+	assert.Equal(t, 6, f.Low)
+	assert.Equal(t, 14, f.High)
+	assert.Len(t, f.Guests, 20)
+}
+
+func TestPredictClampsToRSVPs(t *testing.T) {
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	f, err := Predict(event, rsvps(event, 3), NewHistory(nil, eventTime), fixedModel(0.99), 0.9)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, f.High)
+}
+
+func TestPredictRejectsLevel(t *testing.T) {
+	event := models.NewEvent("Hack Night", "event-1", nil)
+	_, err := Predict(event, nil, NewHistory(nil, time.Now()), fixedModel(0.5), 1)
+	assert.Error(t, err)
+}
+
+func TestZScore(t *testing.T) {
+	assert.InDelta(t, 1.645, zScore(0.9), 1e-3)
+	assert.InDelta(t, 1.960, zScore(0.95), 1e-3)
+}
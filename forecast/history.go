@@ -0,0 +1,77 @@
+// Package forecast estimates how many people will turn up to an event.
+package forecast
+
+import (
+	"time"
+
+	"github.com/alexthemitchell/community-attendance/analytics"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// DefaultShowRate is the show rate assumed before there is any history.
+const DefaultShowRate = 0.6
+
+// ShowRecord counts how often an attendee came after saying yes.
+type ShowRecord struct {
+	RSVPed   int
+	Attended int
+}
+
+// Rate is the attendee's show rate, shrunk towards prior by priorWeight
+// imaginary events so a single RSVP doesn't decide it.
+func (r ShowRecord) Rate(prior, priorWeight float64) float64 {
+	return (float64(r.Attended) + prior*priorWeight) / (float64(r.RSVPed) + priorWeight)
+}
+
+// History is what was known about past events at a cutoff time. Only
+// events that started before the cutoff are counted, so a forecast for an
+// event never sees its own outcome.
+type History struct {
+	cutoff  time.Time
+	records map[string]ShowRecord
+	overall ShowRecord
+}
+
+// NewHistory collects the yes RSVPs for events before cutoff.
+func NewHistory(attendances []*models.Attendance, cutoff time.Time) *History {
+	h := &History{
+		cutoff:  cutoff,
+		records: map[string]ShowRecord{},
+	}
+	checkInsTaken := analytics.CheckInsTaken(attendances)
+	for _, attendance := range attendances {
+		eventTime := attendance.Event().Time()
+		if !attendance.RSVP() || eventTime == nil || !eventTime.Before(cutoff) {
+			continue
+		}
+		userID := attendance.Attendee().UserID()
+		record := h.records[userID]
+		record.RSVPed++
+		h.overall.RSVPed++
+		if analytics.Attended(attendance, checkInsTaken) {
+			record.Attended++
+			h.overall.Attended++
+		}
+		h.records[userID] = record
+	}
+	return h
+}
+
+// Cutoff is the time the history was taken at.
+func (h *History) Cutoff() time.Time {
+	return h.cutoff
+}
+
+// Record is the show record for one attendee.
+func (h *History) Record(userID string) ShowRecord {
+	return h.records[userID]
+}
+
+// BaseRate is the show rate across everyone, or DefaultShowRate when no
+// past RSVPs are known.
+func (h *History) BaseRate() float64 {
+	if h.overall.RSVPed == 0 {
+		return DefaultShowRate
+	}
+	return float64(h.overall.Attended) / float64(h.overall.RSVPed)
+}
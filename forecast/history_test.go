@@ -0,0 +1,31 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestNewHistoryIgnoresLaterEvents(t *testing.T) {
+	cutoff := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	before, after := cutoff.AddDate(0, 0, -7), cutoff
+	attendee := models.NewAttendee("Alex", "", "user 1", nil, nil, false)
+	history := NewHistory([]*models.Attendance{
+		models.NewAttendance(attendee, models.NewEvent("Before", "1", &before), true, nil),
+		models.NewAttendance(attendee, models.NewEvent("Declined", "2", &before), false, nil),
+		models.NewAttendance(attendee, models.NewEvent("After", "3", &after), true, nil),
+	}, cutoff)
+
+	assert.Equal(t, ShowRecord{RSVPed: 1, Attended: 1}, history.Record("user 1"))
+	assert.Equal(t, ShowRecord{}, history.Record("user 2"))
+	assert.Equal(t, 1.0, history.BaseRate())
+	assert.Equal(t, DefaultShowRate, NewHistory(nil, cutoff).BaseRate())
+}
+
+func TestShowRecordRate(t *testing.T) {
+	assert.Equal(t, 0.5, ShowRecord{}.Rate(0.5, 3))
+	assert.InDelta(t, 0.7, ShowRecord{RSVPed: 2, Attended: 2}.Rate(0.5, 3), 1e-9)
+}
@@ -0,0 +1,131 @@
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// Model estimates the chance that someone who RSVPed yes turns up.
+type Model interface {
+	Name() string
+	ShowProbability(attendance *models.Attendance, history *History) float64
+}
+
+// HeuristicModel starts from the attendee's own show rate, shrunk towards
+// the group's, and adjusts its log-odds for host status, RSVP lead time
+// and membership tenure.
+type HeuristicModel struct{}
+
+const (
+	// priorWeight is how many events of the group's base rate an
+	// attendee's own record is blended with.
+	priorWeight = 3
+
+	hostAdjustment = 2.0
+	// RSVPs made within a day of the event are usually firm; ones made
+	// weeks ahead are often forgotten.
+	lateRSVPAdjustment  = 0.4
+	earlyRSVPAdjustment = -0.4
+	lateRSVPLead        = 24 * time.Hour
+	earlyRSVPLead       = 21 * 24 * time.Hour
+	// Members who joined shortly before the event haven't shown their
+	// habits yet and skew towards not showing.
+	newMemberAdjustment = -0.3
+	newMemberTenure     = 30 * 24 * time.Hour
+)
+
+func (HeuristicModel) Name() string {
+	return "heuristic"
+}
+
+func logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
+
+func logistic(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// clampProbability keeps p away from 0 and 1 so log-odds stay finite.
+func clampProbability(p float64) float64 {
+	return math.Min(math.Max(p, 0.01), 0.99)
+}
+
+func (HeuristicModel) ShowProbability(attendance *models.Attendance, history *History) float64 {
+	attendee := attendance.Attendee()
+	rate := history.Record(attendee.UserID()).Rate(history.BaseRate(), priorWeight)
+	x := logit(clampProbability(rate))
+
+	if attendee.IsHost() {
+		x += hostAdjustment
+	}
+	eventTime := attendance.Event().Time()
+	if eventTime != nil {
+		if rsvpTime := attendance.RSVPTime(); rsvpTime != nil {
+			lead := eventTime.Sub(*rsvpTime)
+			switch {
+			case lead < lateRSVPLead:
+				x += lateRSVPAdjustment
+			case lead > earlyRSVPLead:
+				x += earlyRSVPAdjustment
+			}
+		}
+		if joined := attendee.JoinedDate(); joined != nil && eventTime.Sub(*joined) < newMemberTenure {
+			x += newMemberAdjustment
+		}
+	}
+	return logistic(x)
+}
+
+// RSVPModel assumes everyone who says yes turns up, which is what
+// planning straight from the RSVP count does.
+type RSVPModel struct{}
+
+func (RSVPModel) Name() string {
+	return "rsvp"
+}
+
+func (RSVPModel) ShowProbability(*models.Attendance, *History) float64 {
+	return 1
+}
+
+// BaseRateModel gives everyone the group's overall show rate.
+type BaseRateModel struct{}
+
+func (BaseRateModel) Name() string {
+	return "base-rate"
+}
+
+func (BaseRateModel) ShowProbability(attendance *models.Attendance, history *History) float64 {
+	return history.BaseRate()
+}
+
+var registeredModels = []Model{HeuristicModel{}, BaseRateModel{}, RSVPModel{}}
+
+// DefaultModel is the model used when none is chosen.
+func DefaultModel() Model {
+	return HeuristicModel{}
+}
+
+// ModelNames lists the names accepted by ModelByName.
+func ModelNames() []string {
+	names := make([]string, 0, len(registeredModels))
+	for _, model := range registeredModels {
+		names = append(names, model.Name())
+	}
+	return names
+}
+
+// ModelByName finds a model by its name.
+func ModelByName(name string) (Model, error) {
+	for _, model := range registeredModels {
+		if model.Name() == name {
+			return model, nil
+		}
+	}
+	return nil, errors.Errorf("unknown forecast model %#v", name)
+}
@@ -0,0 +1,32 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestHeuristicModel(t *testing.T) {
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	history := NewHistory(nil, eventTime)
+	longAgo := eventTime.AddDate(-1, 0, 0)
+	lastWeek := eventTime.AddDate(0, 0, -7)
+	model := HeuristicModel{}
+
+	member := models.NewAttendee("Member", "", "member", nil, &longAgo, false)
+	base := model.ShowProbability(models.NewAttendance(member, event, true, &lastWeek), history)
+	assert.InDelta(t, DefaultShowRate, base, 1e-9)
+
+	host := models.NewAttendee("Host", "", "host", nil, &longAgo, true)
+	assert.True(t, model.ShowProbability(models.NewAttendance(host, event, true, &lastWeek), history) > base)
+
+	newcomer := models.NewAttendee("New", "", "new", nil, &lastWeek, false)
+	assert.True(t, model.ShowProbability(models.NewAttendance(newcomer, event, true, &lastWeek), history) < base)
+
+	lastMinute := eventTime.Add(-time.Hour)
+	assert.True(t, model.ShowProbability(models.NewAttendance(member, event, true, &lastMinute), history) > base)
+}
@@ -0,0 +1,308 @@
+// Package importer saves parsed attendance records to storage.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+	"github.com/alexthemitchell/community-attendance/timezone"
+)
+
+// Counts tallies upsert results for an import summary.
+type Counts map[storage.UpsertResult]int
+
+func (c Counts) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d unchanged", c[storage.Inserted], c[storage.Updated], c[storage.Unchanged])
+}
+
+// Summary says what an import changed.
+type Summary struct {
+	Event       storage.UpsertResult
+	Attendees   Counts
+	Attendances Counts
+	// Merged counts records whose user ID was merged into another
+	// attendee, and which were saved against that attendee instead.
+	Merged int
+	// Cancelled counts RSVPs from earlier imports that were missing from
+	// this one.
+	Cancelled int
+}
+
+// Source identifies the file an import was read from.
+type Source struct {
+	// FileName is the file's name without its directory.
+	FileName string
+	// SHA256 is the hex encoded hash of the file's contents.
+	SHA256 string
+}
+
+// SourceFromFile names and hashes a file for Persist. name is recorded in
+// place of the file's own name if not empty, for uploads saved under a
+// temporary name.
+func SourceFromFile(fileName, name string) (Source, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return Source{}, errors.Wrapf(err, "error opening file for hashing: %#v", fileName)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return Source{}, errors.Wrapf(err, "error hashing file: %#v", fileName)
+	}
+	if name == "" {
+		name = fileName
+	}
+	return Source{FileName: filepath.Base(name), SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// EventKey says which event an import is for. Only Name and Time are
+// required.
+type EventKey struct {
+	// ID is the event's ID here, if the caller knows it.
+	ID string
+	// SourceID is the event's ID in the system the export came from.
+	SourceID string
+	Name     string
+	// Time is the wall clock time the event starts at; its location is
+	// ignored. It is read in TimeZone, or if that's empty, in the time zone
+	// of the event matched by ID or source ID.
+	Time time.Time
+	// TimeZone, if set, is the IANA name of the event's time zone.
+	TimeZone string
+}
+
+// ResolveEvent finds the event key refers to, so importing the same export
+// twice updates one event instead of creating two. Events are matched by ID
+// if given, otherwise by source ID, and failing that by name and time,
+// ignoring events with a different source ID. The event returned has key's
+// name, time, time zone and source ID; it is new, with a random ID unless
+// key has one, if nothing matched. fallback is the time zone of events that
+// don't name one.
+func ResolveEvent(store storage.Store, key EventKey, fallback *time.Location) (*models.Event, error) {
+	if key.TimeZone != "" {
+		if _, err := time.LoadLocation(key.TimeZone); err != nil {
+			return nil, errors.Wrapf(err, "unknown time zone %#v", key.TimeZone)
+		}
+	}
+	event, err := findEventByID(store, key)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		event, err = findEventByNameAndTime(store, key, fallback)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if event == nil {
+		id := key.ID
+		if id == "" {
+			generated, err := uuid.NewRandom()
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to create random UUID for event")
+			}
+			id = generated.String()
+		}
+		event = models.NewEvent(key.Name, id, nil)
+	}
+
+	details := event.Details()
+	if key.SourceID != "" {
+		details.SourceID = key.SourceID
+	}
+	if key.TimeZone != "" {
+		details.TimeZone = key.TimeZone
+	}
+	eventTime, err := timezone.Date(key.Time, event.WithDetails(details).Location(fallback))
+	if err != nil {
+		return nil, err
+	}
+	return models.NewEvent(key.Name, event.ID(), &eventTime).WithDetails(details), nil
+}
+
+// findEventByID returns the stored event with key's ID or source ID, or nil
+// if there's none. It is an error for several to share the source ID.
+func findEventByID(store storage.Store, key EventKey) (*models.Event, error) {
+	if key.ID != "" {
+		event, err := store.FetchEvent(key.ID)
+		if errors.Cause(err) == storage.ErrNoEntryWithEventID {
+			return nil, nil
+		}
+		return event, err
+	}
+	if key.SourceID != "" {
+		events, err := store.QueryEvents(storage.EventQuery{SourceID: key.SourceID})
+		if err != nil || len(events) == 0 {
+			return nil, err
+		}
+		if len(events) > 1 {
+			var ids []string
+			for _, event := range events {
+				ids = append(ids, event.ID())
+			}
+			return nil, errors.Errorf("%d events have the source ID %#v (%s); give the ID of the one to import into",
+				len(events), key.SourceID, strings.Join(ids, ", "))
+		}
+		return events[0], nil
+	}
+	return nil, nil
+}
+
+// maxZoneOffset bounds how far any time zone's clocks are from UTC, so an
+// event whose clocks showed a given wall clock time started within it of
+// that wall clock time read as UTC.
+const maxZoneOffset = 15 * time.Hour
+
+// findEventByNameAndTime returns the stored event with key's name, ignoring
+// case, and time, or nil if there's none. The wall clock time is read in
+// key's time zone if it has one, otherwise in each candidate event's own.
+// It is an error for several to match.
+func findEventByNameAndTime(store storage.Store, key EventKey, fallback *time.Location) (*models.Event, error) {
+	if key.ID != "" {
+		return nil, nil
+	}
+	var keyLocation *time.Location
+	if key.TimeZone != "" {
+		keyLocation, _ = time.LoadLocation(key.TimeZone)
+	}
+	wall := time.Date(key.Time.Year(), key.Time.Month(), key.Time.Day(),
+		key.Time.Hour(), key.Time.Minute(), key.Time.Second(), 0, time.UTC)
+	from, to := wall.Add(-maxZoneOffset), wall.Add(maxZoneOffset)
+	events, err := store.QueryEvents(storage.EventQuery{From: &from, To: &to, NameContains: key.Name})
+	if err != nil {
+		return nil, err
+	}
+	var matches []*models.Event
+	var ids []string
+	for _, event := range events {
+		sourceID := event.Details().SourceID
+		if !strings.EqualFold(event.Name(), key.Name) || (sourceID != "" && key.SourceID != "" && sourceID != key.SourceID) {
+			continue
+		}
+		location := keyLocation
+		if location == nil {
+			location = event.Location(fallback)
+		}
+		// Wall clock times the zone skips can't match; stored times only
+		// keep whole seconds.
+		eventTime, err := timezone.Date(key.Time, location)
+		if err != nil || event.Time() == nil || !event.Time().Equal(eventTime.Truncate(time.Second)) {
+			continue
+		}
+		matches = append(matches, event)
+		ids = append(ids, event.ID())
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+	return nil, errors.Errorf("%d events are named %#v at that time (%s); give the ID of the one to import into",
+		len(matches), key.Name, strings.Join(ids, ", "))
+}
+
+// Persist saves the event and its attendance records in one transaction,
+// so a bad row can't leave the database half-imported, and adds source to
+// the event's import history. records should be a complete export of the
+// event's RSVPs: stored RSVPs missing from it are marked cancelled, unless
+// the attendee came in person.
+func Persist(store storage.Store, event *models.Event, records []*models.Attendance, source Source) (*Summary, error) {
+	summary := &Summary{
+		Attendees:   Counts{},
+		Attendances: Counts{},
+	}
+	err := store.WithTx(func(tx storage.Store) error {
+		result, err := tx.UpsertEvent(event)
+		if err != nil {
+			return errors.Wrap(err, "error upserting event")
+		}
+		summary.Event = result
+		exported := map[string]bool{}
+		for _, record := range records {
+			userID := record.Attendee().UserID()
+			resolved, err := tx.ResolveUserID(userID)
+			if err != nil {
+				return err
+			}
+			exported[resolved] = true
+			if resolved != userID {
+				// The surviving attendee's details win over those of the
+				// account merged into it.
+				attendee, err := tx.FetchAttendee(resolved)
+				if err != nil {
+					return errors.Wrapf(err, "error fetching attendee %#v merged from %#v", resolved, userID)
+				}
+				record = models.NewAttendance(attendee, record.Event(), record.RSVP(), record.RSVPTime()).
+					WithCheckIn(record.CheckInStatus(), record.CheckInTime(), record.CheckedInBy())
+				summary.Merged++
+			} else {
+				result, err := tx.UpsertAttendee(record.Attendee())
+				if err != nil {
+					return errors.Wrapf(err, "error upserting attendee %#v", userID)
+				}
+				summary.Attendees[result]++
+			}
+			// Exports don't know who came, so keep any recorded check-in.
+			existing, err := tx.FetchAttendance(record.Event().ID(), record.Attendee().UserID())
+			if err == nil && record.CheckInStatus() == models.NotCheckedIn {
+				record = record.WithCheckIn(existing.CheckInStatus(), existing.CheckInTime(), existing.CheckedInBy())
+			} else if err != nil && errors.Cause(err) != storage.ErrNoAttendanceEntry {
+				return errors.Wrap(err, "error fetching attendance")
+			}
+			result, err = tx.UpsertAttendance(record)
+			if err != nil {
+				return errors.Wrapf(err, "error upserting attendance for %#v", record.Attendee().UserID())
+			}
+			summary.Attendances[result]++
+		}
+
+		// An export without any rows is more likely a bad export than
+		// everyone cancelling.
+		if len(records) > 0 {
+			if err := cancelMissing(tx, event.ID(), exported, summary); err != nil {
+				return err
+			}
+		}
+		err = tx.RecordImport(models.NewImport(event.ID(), source.FileName, source.SHA256, time.Now().UTC(), len(records)))
+		return errors.Wrap(err, "error recording import")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// cancelMissing marks the event's RSVPs whose attendee isn't in exported as
+// cancelled. Check-ins and walk-ins are kept as they are, since exports
+// don't know who came.
+func cancelMissing(tx storage.Store, eventID string, exported map[string]bool, summary *Summary) error {
+	attendances, err := tx.GetAttendancesForEvent(eventID)
+	if err != nil {
+		return errors.Wrap(err, "error fetching attendances")
+	}
+	for _, attendance := range attendances {
+		if exported[attendance.Attendee().UserID()] || attendance.Cancelled() || attendance.CameInPerson() {
+			continue
+		}
+		cancelled := models.NewAttendance(attendance.Attendee(), attendance.Event(), false, attendance.RSVPTime()).
+			WithCheckIn(attendance.CheckInStatus(), attendance.CheckInTime(), attendance.CheckedInBy()).
+			WithCancelled(true)
+		if _, err := tx.UpsertAttendance(cancelled); err != nil {
+			return errors.Wrapf(err, "error cancelling attendance for %#v", attendance.Attendee().UserID())
+		}
+		summary.Cancelled++
+	}
+	return nil
+}
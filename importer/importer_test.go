@@ -0,0 +1,237 @@
+package importer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func TestPersistKeepsCheckIns(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	attendee := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false)
+	records := []*models.Attendance{models.NewAttendance(attendee, event, true, nil)}
+
+	summary, err := Persist(store, event, records, Source{})
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Inserted, summary.Event)
+	assert.Equal(t, "1 created, 0 updated, 0 unchanged", summary.Attendances.String())
+
+	_, err = store.UpsertAttendance(records[0].WithCheckIn(models.CheckedIn, &eventTime, "door"))
+	assert.NoError(t, err)
+	summary, err = Persist(store, event, records, Source{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0 created, 0 updated, 1 unchanged", summary.Attendances.String())
+	stored, err := store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CheckedIn, stored.CheckInStatus())
+}
+
+func TestPersistResolvesMergedUserIDs(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	survivor := models.NewAttendee("Alex", "Alex Mitchell", "user 2", nil, nil, false)
+	_, err := store.UpsertAttendee(survivor)
+	assert.NoError(t, err)
+	_, err = store.UpsertAlias("user 1", "user 2")
+	assert.NoError(t, err)
+
+	old := models.NewAttendee("Alex M", "", "user 1", nil, nil, false)
+	summary, err := Persist(store, event, []*models.Attendance{models.NewAttendance(old, event, true, nil)}, Source{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Merged)
+	assert.Equal(t, "0 created, 0 updated, 0 unchanged", summary.Attendees.String())
+
+	stored, err := store.FetchAttendance("event-1", "user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alex", stored.Attendee().PreferredName())
+	_, err = store.FetchAttendee("user 1")
+	assert.Equal(t, storage.ErrNoEntryWithUserID, errors.Cause(err))
+}
+
+func TestPersistCancelsMissingRSVPs(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	rsvp := func(userID string) *models.Attendance {
+		return models.NewAttendance(models.NewAttendee(userID, "", userID, nil, nil, false), event, true, &eventTime)
+	}
+	_, err := Persist(store, event, []*models.Attendance{rsvp("user 1"), rsvp("user 2"), rsvp("user 3")}, Source{})
+	assert.NoError(t, err)
+	walkIn := models.NewAttendance(models.NewAttendee("Walk In", "", "walk-in 1", nil, nil, false), event, false, nil).
+		WithCheckIn(models.WalkIn, &eventTime, "door")
+	_, err = store.UpsertAttendee(walkIn.Attendee())
+	assert.NoError(t, err)
+	_, err = store.UpsertAttendance(walkIn)
+	assert.NoError(t, err)
+	_, err = store.UpsertAttendance(rsvp("user 3").WithCheckIn(models.CheckedIn, &eventTime, "door"))
+	assert.NoError(t, err)
+
+	summary, err := Persist(store, event, []*models.Attendance{rsvp("user 1")}, Source{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Cancelled)
+	cancelled, err := store.FetchAttendance("event-1", "user 2")
+	assert.NoError(t, err)
+	assert.True(t, cancelled.Cancelled())
+	assert.False(t, cancelled.RSVP())
+	assert.Equal(t, eventTime, *cancelled.RSVPTime())
+	for _, userID := range []string{"user 1", "user 3", "walk-in 1"} {
+		kept, err := store.FetchAttendance("event-1", userID)
+		assert.NoError(t, err)
+		assert.False(t, kept.Cancelled(), userID)
+	}
+
+	// Cancelling is only counted once, and RSVPing again undoes it.
+	summary, err = Persist(store, event, []*models.Attendance{rsvp("user 1")}, Source{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Cancelled)
+	_, err = Persist(store, event, []*models.Attendance{rsvp("user 1"), rsvp("user 2")}, Source{})
+	assert.NoError(t, err)
+	restored, err := store.FetchAttendance("event-1", "user 2")
+	assert.NoError(t, err)
+	assert.False(t, restored.Cancelled())
+	assert.True(t, restored.RSVP())
+
+	// An empty export cancels nothing.
+	summary, err = Persist(store, event, nil, Source{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Cancelled)
+}
+
+func TestPersistRecordsSource(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	attendee := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false)
+	before := time.Now().UTC().Truncate(time.Second)
+
+	_, err := Persist(store, event, []*models.Attendance{models.NewAttendance(attendee, event, true, nil)}, Source{FileName: "rsvps.tsv", SHA256: "abc123"})
+	assert.NoError(t, err)
+	imports, err := store.GetImportsForEvent("event-1")
+	assert.NoError(t, err)
+	if assert.Len(t, imports, 1) {
+		assert.Equal(t, "rsvps.tsv", imports[0].FileName())
+		assert.Equal(t, "abc123", imports[0].SHA256())
+		assert.Equal(t, 1, imports[0].Records())
+		assert.False(t, imports[0].ImportedAt().Before(before))
+	}
+}
+
+func TestSourceFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "importer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "upload.tsv")
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("hello\n"), 0644))
+
+	source, err := SourceFromFile(fileName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, Source{FileName: "upload.tsv", SHA256: "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"}, source)
+	source, err = SourceFromFile(fileName, "exports/rsvps.tsv")
+	assert.NoError(t, err)
+	assert.Equal(t, "rsvps.tsv", source.FileName)
+
+	_, err = SourceFromFile(filepath.Join(dir, "missing.tsv"), "")
+	assert.Error(t, err)
+}
+
+func TestResolveEvent(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	stored := models.NewEvent("Hack Night", "event-1", &eventTime).WithDetails(models.EventDetails{VenueName: "The Library"})
+	_, err := store.UpsertEvent(stored)
+	assert.NoError(t, err)
+
+	// Name and time match case-insensitively, keeping the stored details.
+	event, err := ResolveEvent(store, EventKey{Name: "hack night", Time: eventTime, SourceID: "meetup-1"}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", event.ID())
+	assert.Equal(t, "hack night", event.Name())
+	assert.Equal(t, models.EventDetails{VenueName: "The Library", SourceID: "meetup-1"}, event.Details())
+	_, err = store.UpsertEvent(event)
+	assert.NoError(t, err)
+
+	// The source ID matches even after the event is renamed and moved.
+	moved := eventTime.Add(time.Hour)
+	event, err = ResolveEvent(store, EventKey{Name: "Hack Night: Spring", Time: moved, SourceID: "meetup-1"}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", event.ID())
+	assert.Equal(t, moved, *event.Time())
+
+	// A different source ID at the same name and time is a different event.
+	event, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: eventTime, SourceID: "meetup-2"}, time.UTC)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "event-1", event.ID())
+	assert.NotEmpty(t, event.ID())
+
+	// An explicit ID wins, whether or not it exists yet.
+	event, err = ResolveEvent(store, EventKey{ID: "event-2", Name: "Hack Night", Time: eventTime}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "event-2", event.ID())
+	event, err = ResolveEvent(store, EventKey{ID: "event-1", Name: "Board Games", Time: moved}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "The Library", event.Details().VenueName)
+
+	// Duplicate events from before imports were idempotent are ambiguous.
+	_, err = store.UpsertEvent(models.NewEvent("Picnic", "event-3", &eventTime))
+	assert.NoError(t, err)
+	_, err = store.UpsertEvent(models.NewEvent("Picnic", "event-4", &eventTime))
+	assert.NoError(t, err)
+	_, err = ResolveEvent(store, EventKey{Name: "Picnic", Time: eventTime}, time.UTC)
+	assert.EqualError(t, err, `2 events are named "Picnic" at that time (event-3, event-4); give the ID of the one to import into`)
+
+	// So are events sharing a source ID.
+	_, err = store.UpsertEvent(models.NewEvent("Picnic", "event-5", &moved).WithDetails(models.EventDetails{SourceID: "meetup-1"}))
+	assert.NoError(t, err)
+	_, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: eventTime, SourceID: "meetup-1"}, time.UTC)
+	assert.EqualError(t, err, `2 events have the source ID "meetup-1" (event-1, event-5); give the ID of the one to import into`)
+}
+
+func TestResolveEventTimeZones(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+	wall := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+
+	// The wall clock is read in the key's time zone, which the event keeps.
+	event, err := ResolveEvent(store, EventKey{ID: "event-1", Name: "Hack Night", Time: wall, TimeZone: "America/Los_Angeles"}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), event.Time().UTC())
+	assert.Equal(t, "America/Los_Angeles", event.Details().TimeZone)
+	_, err = store.UpsertEvent(event)
+	assert.NoError(t, err)
+
+	// Otherwise it's read in the matched event's zone, then the fallback.
+	event, err = ResolveEvent(store, EventKey{ID: "event-1", Name: "Hack Night", Time: wall}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), event.Time().UTC())
+	event, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: wall}, losAngeles)
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", event.ID())
+	// The stored event's zone decides, whatever the fallback.
+	event, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: wall}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", event.ID())
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), event.Time().UTC())
+	event, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: wall, TimeZone: "America/New_York"}, time.UTC)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "event-1", event.ID())
+
+	_, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: wall, TimeZone: "Mars/Olympus_Mons"}, time.UTC)
+	assert.Error(t, err)
+	// 2:30 AM doesn't happen on the day clocks spring forward.
+	skipped := time.Date(2019, time.March, 10, 2, 30, 0, 0, time.UTC)
+	_, err = ResolveEvent(store, EventKey{Name: "Hack Night", Time: skipped}, losAngeles)
+	assert.Error(t, err)
+}
@@ -2,11 +2,33 @@ package models
 
 import "time"
 
+// CheckInStatus records what happened at the door, as opposed to what the
+// attendee said they would do.
+type CheckInStatus string
+
+const (
+	// NotCheckedIn means no check-in has been recorded either way.
+	NotCheckedIn CheckInStatus = ""
+	CheckedIn    CheckInStatus = "checked-in"
+	NoShow       CheckInStatus = "no-show"
+	// WalkIn is someone who came without RSVPing yes.
+	WalkIn CheckInStatus = "walk-in"
+)
+
+// CheckInStatuses lists every recorded check-in status.
+func CheckInStatuses() []CheckInStatus {
+	return []CheckInStatus{CheckedIn, NoShow, WalkIn}
+}
+
 type Attendance struct {
-	attendee *Attendee
-	event    *Event
-	rsvpTime *time.Time
-	rsvp     bool
+	attendee      *Attendee
+	event         *Event
+	rsvpTime      *time.Time
+	rsvp          bool
+	checkInStatus CheckInStatus
+	checkInTime   *time.Time
+	checkedInBy   string
+	cancelled     bool
 }
 
 func NewAttendance(attendee *Attendee, event *Event, rsvp bool, rsvpTime *time.Time) *Attendance {
@@ -18,6 +40,22 @@ func NewAttendance(attendee *Attendee, event *Event, rsvp bool, rsvpTime *time.T
 	}
 }
 
+// WithCheckIn returns a copy of the attendance with its check-in replaced.
+func (a *Attendance) WithCheckIn(status CheckInStatus, checkInTime *time.Time, checkedInBy string) *Attendance {
+	c := *a
+	c.checkInStatus = status
+	c.checkInTime = checkInTime
+	c.checkedInBy = checkedInBy
+	return &c
+}
+
+// WithCancelled returns a copy of the attendance marked as cancelled or not.
+func (a *Attendance) WithCancelled(cancelled bool) *Attendance {
+	c := *a
+	c.cancelled = cancelled
+	return &c
+}
+
 func (a *Attendance) Attendee() *Attendee {
 	return a.attendee
 }
@@ -33,3 +71,27 @@ func (a *Attendance) RSVPTime() *time.Time {
 func (a *Attendance) RSVP() bool {
 	return a.rsvp
 }
+
+func (a *Attendance) CheckInStatus() CheckInStatus {
+	return a.checkInStatus
+}
+
+func (a *Attendance) CheckInTime() *time.Time {
+	return a.checkInTime
+}
+
+// CheckedInBy names whoever recorded the check-in.
+func (a *Attendance) CheckedInBy() string {
+	return a.checkedInBy
+}
+
+// CameInPerson reports whether a check-in or walk-in was recorded.
+func (a *Attendance) CameInPerson() bool {
+	return a.checkInStatus == CheckedIn || a.checkInStatus == WalkIn
+}
+
+// Cancelled reports whether the RSVP disappeared from a later export of the
+// event, meaning the attendee withdrew it.
+func (a *Attendance) Cancelled() bool {
+	return a.cancelled
+}
@@ -12,6 +12,7 @@ type Attendee struct {
 	profileURL    *url.URL
 	isHost        bool
 	joinedDate    *time.Time
+	email         string
 }
 
 func (a *Attendee) PreferredName() string {
@@ -34,6 +35,18 @@ func (a *Attendee) JoinedDate() *time.Time {
 	return a.joinedDate
 }
 
+// Email is the attendee's email address, or "" if it isn't known.
+func (a *Attendee) Email() string {
+	return a.email
+}
+
+// WithEmail returns a copy of the attendee with the given email address.
+func (a *Attendee) WithEmail(email string) *Attendee {
+	c := *a
+	c.email = email
+	return &c
+}
+
 func NewAttendee(preferredName, legalName, userID string, profileURL *url.URL, joinedDate *time.Time, isHost bool) *Attendee {
 	return &Attendee{
 		preferredName: preferredName,
@@ -3,9 +3,30 @@ package models
 import "time"
 
 type Event struct {
-	name string
-	id   string
-	time *time.Time
+	name    string
+	id      string
+	time    *time.Time
+	details EventDetails
+}
+
+// EventDetails are the optional facts about an event beyond its name and
+// time. Zero values mean unknown.
+type EventDetails struct {
+	VenueName    string
+	VenueAddress string
+	// Capacity is how many people the venue holds.
+	Capacity int
+	// Group is the organizing group.
+	Group string
+	// SeriesID ties together the events of a recurring series.
+	SeriesID string
+	Duration time.Duration
+	// TimeZone is the IANA name of the zone the event takes place in, such
+	// as "America/Los_Angeles".
+	TimeZone string
+	// SourceID identifies the event in the system it was imported from,
+	// such as its Meetup event ID.
+	SourceID string
 }
 
 func NewEvent(name, id string, time *time.Time) *Event {
@@ -27,3 +48,39 @@ func (e *Event) Time() *time.Time {
 func (e *Event) ID() string {
 	return e.id
 }
+
+func (e *Event) Details() EventDetails {
+	return e.details
+}
+
+// WithDetails returns a copy of the event with its details replaced.
+func (e *Event) WithDetails(details EventDetails) *Event {
+	c := *e
+	c.details = details
+	return &c
+}
+
+// Location returns the event's time zone, or fallback if it has none or
+// the zone isn't known.
+func (e *Event) Location(fallback *time.Location) *time.Location {
+	if e.details.TimeZone == "" {
+		return fallback
+	}
+	location, err := time.LoadLocation(e.details.TimeZone)
+	if err != nil {
+		return fallback
+	}
+	return location
+}
+
+// Local returns a copy of the event with its time in the event's time
+// zone, or in fallback if it has none, for showing to people.
+func (e *Event) Local(fallback *time.Location) *Event {
+	if e.time == nil {
+		return e
+	}
+	c := *e
+	local := e.time.In(e.Location(fallback))
+	c.time = &local
+	return &c
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Import records a file that was imported into an event, so the origin of
+// its attendance records can be traced.
+type Import struct {
+	eventID    string
+	fileName   string
+	sha256     string
+	importedAt time.Time
+	records    int
+}
+
+func NewImport(eventID, fileName, sha256 string, importedAt time.Time, records int) *Import {
+	return &Import{
+		eventID:    eventID,
+		fileName:   fileName,
+		sha256:     sha256,
+		importedAt: importedAt,
+		records:    records,
+	}
+}
+
+func (i *Import) EventID() string {
+	return i.eventID
+}
+
+// FileName is the name of the imported file, without its directory.
+func (i *Import) FileName() string {
+	return i.fileName
+}
+
+// SHA256 is the hex encoded SHA-256 hash of the file's contents.
+func (i *Import) SHA256() string {
+	return i.sha256
+}
+
+func (i *Import) ImportedAt() time.Time {
+	return i.importedAt
+}
+
+// Records counts the attendance records read from the file.
+func (i *Import) Records() int {
+	return i.records
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func (s *Server) attendees(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	attendees, err := s.store.GetAllAttendees()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list := make([]*attendeeJSON, 0, len(attendees))
+	for _, a := range attendees {
+		list = append(list, newAttendeeJSON(a))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// attendee serves /api/attendees/{user-id} and the attendances below it.
+func (s *Server) attendee(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r, "/api/attendees/")
+	switch {
+	case len(parts) == 1:
+		s.attendeeByID(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "attendances":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		if _, err := s.store.FetchAttendee(parts[0]); err != nil {
+			writeError(w, err)
+			return
+		}
+		attendances, err := s.store.GetAttendancesForAttendee(parts[0])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, attendancesJSON(attendances))
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) attendeeByID(w http.ResponseWriter, r *http.Request, userID string) {
+	switch r.Method {
+	case http.MethodGet:
+		attendee, err := s.store.FetchAttendee(userID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newAttendeeJSON(attendee))
+	case http.MethodPut:
+		var body attendeeJSON
+		if err := decodeJSON(r, &body); err != nil {
+			writeError(w, err)
+			return
+		}
+		body.UserID = userID
+		attendee, err := body.model()
+		if err != nil {
+			writeError(w, badRequest{err})
+			return
+		}
+		result, err := s.store.UpsertAttendee(attendee)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if result == storage.Inserted {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, newAttendeeJSON(attendee))
+	case http.MethodDelete:
+		// Deleting the attendee deletes their attendances too.
+		err := s.store.WithTx(func(tx storage.Store) error {
+			if _, err := tx.FetchAttendee(userID); err != nil {
+				return err
+			}
+			return tx.DeleteAttendee(userID)
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttendees(t *testing.T) {
+	s := newTestServer()
+	path := "/api/attendees/" + url.PathEscape("user 1")
+
+	body := &attendeeJSON{PreferredName: "Alex", LegalName: "Alex Mitchell", ProfileURL: "https://www.meetup.com/members/1/"}
+	var saved attendeeJSON
+	assert.Equal(t, http.StatusCreated, doJSON(t, s, http.MethodPut, path, body, &saved))
+	assert.Equal(t, "user 1", saved.UserID)
+	body.IsHost = true
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodPut, path, body, &saved))
+
+	var fetched attendeeJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, path, nil, &fetched))
+	assert.True(t, fetched.IsHost)
+	assert.Equal(t, "https://www.meetup.com/members/1/", fetched.ProfileURL)
+
+	var list []*attendeeJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/attendees", nil, &list))
+	assert.Len(t, list, 1)
+
+	var attendances []*attendanceJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, path+"/attendances", nil, &attendances))
+	assert.Empty(t, attendances)
+
+	assert.Equal(t, http.StatusNoContent, doJSON(t, s, http.MethodDelete, path, nil, nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodGet, path, nil, nil))
+}
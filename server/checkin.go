@@ -0,0 +1,153 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// maxSearchResults bounds the matches returned to the kiosk.
+const maxSearchResults = 50
+
+// defaultCheckInBy is recorded for check-ins that don't say who made them.
+const defaultCheckInBy = "kiosk"
+
+type checkInRequestJSON struct {
+	UserID        string `json:"user_id"`
+	PreferredName string `json:"preferred_name"`
+	LegalName     string `json:"legal_name"`
+	By            string `json:"by"`
+}
+
+type checkInResultJSON struct {
+	Counts      checkin.Counts    `json:"counts"`
+	Attendances []*attendanceJSON `json:"attendances"`
+}
+
+type closeResultJSON struct {
+	Counts  checkin.Counts `json:"counts"`
+	NoShows int            `json:"no_shows"`
+}
+
+// session opens a check-in session for the event. Sessions are made per
+// request so every kiosk sees the check-ins made at the others.
+func (s *Server) session(eventID, by string) (*checkin.Session, error) {
+	if strings.TrimSpace(by) == "" {
+		by = defaultCheckInBy
+	}
+	return checkin.NewSession(s.store, eventID, by)
+}
+
+// checkIns serves /api/events/{id}/checkins: searching the RSVP list, and
+// checking people in.
+func (s *Server) checkIns(w http.ResponseWriter, r *http.Request, eventID string) {
+	switch r.Method {
+	case http.MethodGet:
+		session, err := s.session(eventID, "")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		matches := session.Search(r.URL.Query().Get("q"))
+		if len(matches) > maxSearchResults {
+			matches = matches[:maxSearchResults]
+		}
+		writeJSON(w, http.StatusOK, &checkInResultJSON{Counts: session.Counts(), Attendances: attendancesJSON(matches)})
+	case http.MethodPost:
+		var body checkInRequestJSON
+		if err := decodeJSON(r, &body); err != nil {
+			writeError(w, err)
+			return
+		}
+		if body.UserID == "" {
+			writeError(w, badRequest{errors.New("check-in needs a user_id")})
+			return
+		}
+		session, err := s.session(eventID, body.By)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		attendance, err := session.CheckIn(body.UserID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, &checkInResultJSON{Counts: session.Counts(), Attendances: attendancesJSON([]*models.Attendance{attendance})})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// checkIn serves /api/events/{id}/checkins/{user-id}, where DELETE undoes
+// a check-in.
+func (s *Server) checkIn(w http.ResponseWriter, r *http.Request, eventID, userID string) {
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	session, err := s.session(eventID, "")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	attendance, err := session.Undo(userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &checkInResultJSON{Counts: session.Counts(), Attendances: attendancesJSON([]*models.Attendance{attendance})})
+}
+
+// closeEvent serves /api/events/{id}/close, ending check-in by marking
+// everyone who RSVPed yes and wasn't checked in as a no-show.
+func (s *Server) closeEvent(w http.ResponseWriter, r *http.Request, eventID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	session, err := s.session(eventID, "")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	noShows, err := session.Close()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &closeResultJSON{Counts: session.Counts(), NoShows: noShows})
+}
+
+// walkIns serves /api/events/{id}/walkins, registering someone with no
+// stored record.
+func (s *Server) walkIns(w http.ResponseWriter, r *http.Request, eventID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var body checkInRequestJSON
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+	session, err := s.session(eventID, body.By)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if strings.TrimSpace(body.PreferredName) == "" && strings.TrimSpace(body.LegalName) == "" {
+		writeError(w, badRequest{errors.New("a walk-in needs a name")})
+		return
+	}
+	attendance, err := session.AddWalkIn(body.PreferredName, body.LegalName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, &checkInResultJSON{Counts: session.Counts(), Attendances: attendancesJSON([]*models.Attendance{attendance})})
+}
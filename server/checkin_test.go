@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/checkin"
+	"github.com/alexthemitchell/community-attendance/models"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func TestCheckIns(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	attendee := models.NewAttendee("Alex", "Alex Mitchell", "user 1", nil, nil, false)
+	_, err := store.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = store.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	_, err = store.UpsertAttendance(models.NewAttendance(attendee, event, true, nil))
+	assert.NoError(t, err)
+	s := New(store, time.UTC)
+
+	var result checkInResultJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/events/event-1/checkins?q=amit", nil, &result))
+	assert.Len(t, result.Attendances, 1)
+	assert.Equal(t, checkin.Counts{RSVPs: 1}, result.Counts)
+
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodPost, "/api/events/event-1/checkins", &checkInRequestJSON{UserID: "user 1"}, &result))
+	assert.Equal(t, 1, result.Counts.CheckedIn)
+	stored, err := store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CheckedIn, stored.CheckInStatus())
+	assert.Equal(t, "kiosk", stored.CheckedInBy())
+
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodDelete, "/api/events/event-1/checkins/"+url.PathEscape("user 1"), nil, &result))
+	assert.Equal(t, 0, result.Counts.CheckedIn)
+
+	assert.Equal(t, http.StatusCreated, doJSON(t, s, http.MethodPost, "/api/events/event-1/walkins", &checkInRequestJSON{PreferredName: "Sam", By: "Jo"}, &result))
+	assert.Equal(t, 1, result.Counts.WalkIns)
+	if assert.Len(t, result.Attendances, 1) {
+		assert.Equal(t, "walk-in", result.Attendances[0].CheckInStatus)
+		assert.Equal(t, "Jo", result.Attendances[0].CheckedInBy)
+	}
+
+	var closed closeResultJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodPost, "/api/events/event-1/close", nil, &closed))
+	assert.Equal(t, 1, closed.NoShows)
+	stored, err = store.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NoShow, stored.CheckInStatus())
+	assert.Equal(t, http.StatusMethodNotAllowed, doJSON(t, s, http.MethodGet, "/api/events/event-1/close", nil, nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodPost, "/api/events/nope/close", nil, nil))
+
+	assert.Equal(t, http.StatusBadRequest, doJSON(t, s, http.MethodPost, "/api/events/event-1/walkins", &checkInRequestJSON{}, nil))
+	assert.Equal(t, http.StatusBadRequest, doJSON(t, s, http.MethodPost, "/api/events/event-1/checkins", &checkInRequestJSON{}, nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodPost, "/api/events/event-1/checkins", &checkInRequestJSON{UserID: "nobody"}, nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodGet, "/api/events/nope/checkins", nil, nil))
+}
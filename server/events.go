@@ -0,0 +1,151 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		events, err := s.store.GetAllEvents()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		list := make([]*eventJSON, 0, len(events))
+		for _, e := range events {
+			list = append(list, newEventJSON(e, s.location))
+		}
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		s.createEvent(w, r)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) createEvent(w http.ResponseWriter, r *http.Request) {
+	var body eventJSON
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+	if body.ID == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			writeError(w, errors.Wrap(err, "unable to create random UUID for event"))
+			return
+		}
+		body.ID = id.String()
+	} else if _, err := s.store.FetchEvent(body.ID); err == nil {
+		writeJSON(w, http.StatusConflict, &errorJSON{Error: "an event with that ID already exists"})
+		return
+	} else if errors.Cause(err) != storage.ErrNoEntryWithEventID {
+		writeError(w, err)
+		return
+	}
+	event, err := body.model()
+	if err != nil {
+		writeError(w, badRequest{err})
+		return
+	}
+	if _, err := s.store.UpsertEvent(event); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/events/"+event.ID())
+	writeJSON(w, http.StatusCreated, newEventJSON(event, s.location))
+}
+
+// event serves /api/events/{id} and the collections below it.
+func (s *Server) event(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r, "/api/events/")
+	switch {
+	case len(parts) == 1:
+		s.eventByID(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "attendances":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		if _, err := s.store.FetchEvent(parts[0]); err != nil {
+			writeError(w, err)
+			return
+		}
+		attendances, err := s.store.GetAttendancesForEvent(parts[0])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, attendancesJSON(attendances))
+	case len(parts) == 2 && parts[1] == "checkins":
+		s.checkIns(w, r, parts[0])
+	case len(parts) == 3 && parts[1] == "checkins":
+		s.checkIn(w, r, parts[0], parts[2])
+	case len(parts) == 2 && parts[1] == "walkins":
+		s.walkIns(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "close":
+		s.closeEvent(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "import":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.importFile(w, r, parts[0])
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) eventByID(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		event, err := s.store.FetchEvent(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newEventJSON(event, s.location))
+	case http.MethodPut:
+		if _, err := s.store.FetchEvent(id); err != nil {
+			writeError(w, err)
+			return
+		}
+		var body eventJSON
+		if err := decodeJSON(r, &body); err != nil {
+			writeError(w, err)
+			return
+		}
+		body.ID = id
+		event, err := body.model()
+		if err != nil {
+			writeError(w, badRequest{err})
+			return
+		}
+		if _, err := s.store.UpsertEvent(event); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newEventJSON(event, s.location))
+	case http.MethodDelete:
+		// Deleting the event deletes its attendances and imports too.
+		err := s.store.WithTx(func(tx storage.Store) error {
+			if _, err := tx.FetchEvent(id); err != nil {
+				return err
+			}
+			return tx.DeleteEvent(id)
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func TestEvents(t *testing.T) {
+	s := newTestServer()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+
+	var created eventJSON
+	assert.Equal(t, http.StatusCreated, doJSON(t, s, http.MethodPost, "/api/events", &eventJSON{Name: "Hack Night", Time: &eventTime}, &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, http.StatusBadRequest, doJSON(t, s, http.MethodPost, "/api/events", &eventJSON{Name: "No Time"}, nil))
+	assert.Equal(t, http.StatusConflict, doJSON(t, s, http.MethodPost, "/api/events", &created, nil))
+
+	var list []*eventJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/events", nil, &list))
+	assert.Len(t, list, 1)
+
+	later := eventTime.Add(time.Hour)
+	var updated eventJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodPut, "/api/events/"+created.ID, &eventJSON{Name: "Hack Night #2", Time: &later}, &updated))
+	var fetched eventJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/events/"+created.ID, nil, &fetched))
+	assert.Equal(t, "Hack Night #2", fetched.Name)
+	assert.Equal(t, later, *fetched.Time)
+
+	var attendances []*attendanceJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/events/"+created.ID+"/attendances", nil, &attendances))
+	assert.Empty(t, attendances)
+
+	assert.Equal(t, http.StatusNoContent, doJSON(t, s, http.MethodDelete, "/api/events/"+created.ID, nil, nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodDelete, "/api/events/"+created.ID, nil, nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodPut, "/api/events/"+created.ID, &updated, nil))
+}
+
+func TestEventDetails(t *testing.T) {
+	s := newTestServer()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	event := &eventJSON{
+		Name:            "Hack Night",
+		Time:            &eventTime,
+		VenueName:       "The Library",
+		Capacity:        40,
+		DurationMinutes: 150,
+		TimeZone:        "America/Los_Angeles",
+		SourceID:        "259874361",
+	}
+
+	var created eventJSON
+	assert.Equal(t, http.StatusCreated, doJSON(t, s, http.MethodPost, "/api/events", event, &created))
+	var fetched eventJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/events/"+created.ID, nil, &fetched))
+	assert.Equal(t, "The Library", fetched.VenueName)
+	assert.Equal(t, 40, fetched.Capacity)
+	assert.Equal(t, 150, fetched.DurationMinutes)
+	assert.Equal(t, "America/Los_Angeles", fetched.TimeZone)
+	// Times are given in the event's time zone.
+	assert.Equal(t, "2019-03-14T11:30:00-07:00", fetched.Time.Format(time.RFC3339))
+	assert.Equal(t, "259874361", fetched.SourceID)
+
+	event.TimeZone = "Pacific Time"
+	assert.Equal(t, http.StatusBadRequest, doJSON(t, s, http.MethodPost, "/api/events", event, nil))
+}
+
+// brokenEventStore fails every event lookup.
+type brokenEventStore struct {
+	storage.Store
+}
+
+func (brokenEventStore) FetchEvent(id string) (*models.Event, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestCreateEventReportsLookupErrors(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	s := New(brokenEventStore{store}, time.UTC)
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	var e errorJSON
+	assert.Equal(t, http.StatusInternalServerError, doJSON(t, s, http.MethodPost, "/api/events", &eventJSON{ID: "event-1", Name: "Hack Night", Time: &eventTime}, &e))
+	assert.Contains(t, e.Error, "database is locked")
+	events, err := store.GetAllEvents()
+	assert.NoError(t, err)
+	assert.Empty(t, events, "nothing is saved when the ID can't be checked")
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/cli/reader"
+	"github.com/alexthemitchell/community-attendance/importer"
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func countsJSON(c importer.Counts) map[string]int {
+	return map[string]int{
+		"created":   c[storage.Inserted],
+		"updated":   c[storage.Updated],
+		"unchanged": c[storage.Unchanged],
+	}
+}
+
+// saveUpload copies the uploaded file to a temporary file with the same
+// extension, since the readers detect formats by name and open files
+// themselves. It returns the name the file was uploaded under as
+// uploadName. The caller removes the returned directory.
+func saveUpload(r *http.Request) (dir, fileName, uploadName string, err error) {
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		return "", "", "", badRequest{errors.Wrap(err, "expected a multipart upload in the \"file\" field")}
+	}
+	defer upload.Close()
+	dir, err = ioutil.TempDir("", "attendance-upload")
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "error creating upload directory")
+	}
+	fileName = filepath.Join(dir, "upload"+filepath.Ext(header.Filename))
+	file, err := os.Create(fileName)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", "", "", errors.Wrap(err, "error saving upload")
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, upload); err != nil {
+		os.RemoveAll(dir)
+		return "", "", "", errors.Wrap(err, "error saving upload")
+	}
+	return dir, fileName, header.Filename, nil
+}
+
+// importFile reads an uploaded attendee export into an existing event.
+// Form fields "format", "sheet" and "legal_name_question" work like the
+// import command's flags.
+func (s *Server) importFile(w http.ResponseWriter, r *http.Request, eventID string) {
+	event, err := s.store.FetchEvent(eventID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		writeError(w, badRequest{errors.Wrap(err, "error reading upload")})
+		return
+	}
+	dir, fileName, uploadName, err := saveUpload(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	options := reader.DefaultOptions()
+	if question := r.FormValue("legal_name_question"); question != "" {
+		options.Columns.LegalNameQuestion = question
+	}
+	options.Sheet = r.FormValue("sheet")
+	options.Location = event.Location(s.location)
+	records, errs := reader.ParseFile(fileName, r.FormValue("format"), event, options)
+	if len(errs) > 0 {
+		writeError(w, badRequest{errors.Wrap(errs[0], "error reading upload")})
+		return
+	}
+	source, err := importer.SourceFromFile(fileName, uploadName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary, err := importer.Persist(s.store, event, records, source)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result := &importJSON{
+		Records:     len(records),
+		Event:       summary.Event.String(),
+		Attendees:   countsJSON(summary.Attendees),
+		Attendances: countsJSON(summary.Attendances),
+		Merged:      summary.Merged,
+		Cancelled:   summary.Cancelled,
+	}
+	writeJSON(w, http.StatusOK, result)
+}
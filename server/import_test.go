@@ -0,0 +1,52 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportFile(t *testing.T) {
+	s := newTestServer()
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	var event eventJSON
+	assert.Equal(t, http.StatusCreated, doJSON(t, s, http.MethodPost, "/api/events", &eventJSON{Name: "Hack Night", Time: &eventTime}, &event))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "validexample.tsv")
+	assert.NoError(t, err)
+	file, err := os.Open("../cli/reader/test_files/validexample.tsv")
+	assert.NoError(t, err)
+	defer file.Close()
+	_, err = io.Copy(part, file)
+	assert.NoError(t, err)
+	assert.NoError(t, form.Close())
+
+	var summary importJSON
+	assert.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/api/events/"+event.ID+"/import", &body, form.FormDataContentType(), &summary))
+	// The example repeats a user ID, so the second row updates the first.
+	assert.Equal(t, 4, summary.Records)
+	assert.Equal(t, map[string]int{"created": 3, "updated": 1, "unchanged": 0}, summary.Attendances)
+
+	var attendances []*attendanceJSON
+	assert.Equal(t, http.StatusOK, doJSON(t, s, http.MethodGet, "/api/events/"+event.ID+"/attendances", nil, &attendances))
+	assert.Len(t, attendances, 3)
+
+	imports, err := s.store.GetImportsForEvent(event.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, imports, 1) {
+		assert.Equal(t, "validexample.tsv", imports[0].FileName())
+		assert.Len(t, imports[0].SHA256(), 64)
+	}
+
+	var e errorJSON
+	assert.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/api/events/"+event.ID+"/import", bytes.NewBufferString(""), "text/plain", &e))
+	assert.Equal(t, http.StatusNotFound, do(t, s, http.MethodPost, "/api/events/nope/import", bytes.NewBufferString(""), "text/plain", &e))
+}
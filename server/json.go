@@ -0,0 +1,155 @@
+package server
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// The API's JSON bodies. Field names are part of the API, documented in
+// openapi.go, and must not change.
+
+type eventJSON struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Time            *time.Time `json:"time"`
+	VenueName       string     `json:"venue_name,omitempty"`
+	VenueAddress    string     `json:"venue_address,omitempty"`
+	Capacity        int        `json:"capacity,omitempty"`
+	Group           string     `json:"group,omitempty"`
+	SeriesID        string     `json:"series_id,omitempty"`
+	DurationMinutes int        `json:"duration_minutes,omitempty"`
+	TimeZone        string     `json:"time_zone,omitempty"`
+	SourceID        string     `json:"source_id,omitempty"`
+}
+
+// newEventJSON gives the event's time in its time zone, or in location if
+// it has none.
+func newEventJSON(e *models.Event, location *time.Location) *eventJSON {
+	e = e.Local(location)
+	d := e.Details()
+	return &eventJSON{
+		ID:              e.ID(),
+		Name:            e.Name(),
+		Time:            e.Time(),
+		VenueName:       d.VenueName,
+		VenueAddress:    d.VenueAddress,
+		Capacity:        d.Capacity,
+		Group:           d.Group,
+		SeriesID:        d.SeriesID,
+		DurationMinutes: int(d.Duration / time.Minute),
+		TimeZone:        d.TimeZone,
+		SourceID:        d.SourceID,
+	}
+}
+
+func (e *eventJSON) model() (*models.Event, error) {
+	if e.Name == "" {
+		return nil, errors.New("event needs a name")
+	}
+	if e.Time == nil {
+		return nil, errors.New("event needs a time")
+	}
+	if e.Capacity < 0 || e.DurationMinutes < 0 {
+		return nil, errors.New("event capacity and duration must not be negative")
+	}
+	if e.TimeZone != "" {
+		if _, err := time.LoadLocation(e.TimeZone); err != nil {
+			return nil, errors.Wrapf(err, "unknown time zone %#v", e.TimeZone)
+		}
+	}
+	return models.NewEvent(e.Name, e.ID, e.Time).WithDetails(models.EventDetails{
+		VenueName:    e.VenueName,
+		VenueAddress: e.VenueAddress,
+		Capacity:     e.Capacity,
+		Group:        e.Group,
+		SeriesID:     e.SeriesID,
+		Duration:     time.Duration(e.DurationMinutes) * time.Minute,
+		TimeZone:     e.TimeZone,
+		SourceID:     e.SourceID,
+	}), nil
+}
+
+type attendeeJSON struct {
+	UserID        string     `json:"user_id"`
+	PreferredName string     `json:"preferred_name"`
+	LegalName     string     `json:"legal_name"`
+	ProfileURL    string     `json:"profile_url,omitempty"`
+	IsHost        bool       `json:"is_host"`
+	JoinedDate    *time.Time `json:"joined_date,omitempty"`
+	Email         string     `json:"email,omitempty"`
+}
+
+func newAttendeeJSON(a *models.Attendee) *attendeeJSON {
+	j := &attendeeJSON{
+		UserID:        a.UserID(),
+		PreferredName: a.PreferredName(),
+		LegalName:     a.LegalName(),
+		IsHost:        a.IsHost(),
+		JoinedDate:    a.JoinedDate(),
+		Email:         a.Email(),
+	}
+	if a.ProfileURL() != nil {
+		j.ProfileURL = a.ProfileURL().String()
+	}
+	return j
+}
+
+func (a *attendeeJSON) model() (*models.Attendee, error) {
+	if a.UserID == "" {
+		return nil, errors.New("attendee needs a user_id")
+	}
+	profileURL, err := url.Parse(a.ProfileURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing profile URL %#v", a.ProfileURL)
+	}
+	return models.NewAttendee(a.PreferredName, a.LegalName, a.UserID, profileURL, a.JoinedDate, a.IsHost).WithEmail(a.Email), nil
+}
+
+type attendanceJSON struct {
+	EventID       string        `json:"event_id"`
+	Attendee      *attendeeJSON `json:"attendee"`
+	RSVP          bool          `json:"rsvp"`
+	RSVPTime      *time.Time    `json:"rsvp_time,omitempty"`
+	CheckInStatus string        `json:"check_in_status,omitempty"`
+	CheckInTime   *time.Time    `json:"check_in_time,omitempty"`
+	CheckedInBy   string        `json:"checked_in_by,omitempty"`
+	Cancelled     bool          `json:"cancelled,omitempty"`
+}
+
+func newAttendanceJSON(a *models.Attendance) *attendanceJSON {
+	return &attendanceJSON{
+		EventID:       a.Event().ID(),
+		Attendee:      newAttendeeJSON(a.Attendee()),
+		RSVP:          a.RSVP(),
+		RSVPTime:      a.RSVPTime(),
+		CheckInStatus: string(a.CheckInStatus()),
+		CheckInTime:   a.CheckInTime(),
+		CheckedInBy:   a.CheckedInBy(),
+		Cancelled:     a.Cancelled(),
+	}
+}
+
+func attendancesJSON(attendances []*models.Attendance) []*attendanceJSON {
+	list := make([]*attendanceJSON, 0, len(attendances))
+	for _, a := range attendances {
+		list = append(list, newAttendanceJSON(a))
+	}
+	return list
+}
+
+type importJSON struct {
+	Records     int            `json:"records"`
+	Event       string         `json:"event"`
+	Attendees   map[string]int `json:"attendees"`
+	Attendances map[string]int `json:"attendances"`
+	Merged      int            `json:"merged"`
+	Cancelled   int            `json:"cancelled"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,278 @@
+package server
+
+import "net/http"
+
+// kiosk serves the check-in page. The page is self-contained, with no
+// external scripts, styles or fonts, so it works on venue Wi-Fi that can't
+// reach the internet.
+func (s *Server) kiosk(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/kiosk/" {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write([]byte(kioskPage))
+}
+
+func (s *Server) root(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		notFound(w)
+		return
+	}
+	http.Redirect(w, r, "/kiosk/", http.StatusFound)
+}
+
+const kioskPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Check-in</title>
+<style>
+  * { box-sizing: border-box; }
+  body { margin: 0; font: 18px/1.4 -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; background: #f4f4f4; color: #222; }
+  header { background: #263238; color: #fff; padding: 12px 16px; display: flex; flex-wrap: wrap; align-items: center; gap: 12px; }
+  header select { font-size: 18px; padding: 6px; max-width: 100%; }
+  #counts { margin-left: auto; font-size: 20px; }
+  #counts b { font-size: 28px; }
+  main { padding: 16px; max-width: 900px; margin: 0 auto; }
+  input[type=search], input[type=text] { width: 100%; font-size: 24px; padding: 12px; border: 2px solid #90a4ae; border-radius: 6px; }
+  ul { list-style: none; padding: 0; margin: 16px 0; }
+  li { background: #fff; border-radius: 6px; margin-bottom: 8px; padding: 12px 16px; display: flex; align-items: center; gap: 12px; }
+  li .name { flex: 1; }
+  li .legal, li .note { color: #607d8b; font-size: 15px; }
+  li.present { background: #e8f5e9; }
+  button { font-size: 18px; padding: 12px 20px; border: 0; border-radius: 6px; background: #1e88e5; color: #fff; cursor: pointer; }
+  button.secondary { background: #b0bec5; color: #222; }
+  button:disabled { opacity: .5; }
+  #walkin { background: #fff; border-radius: 6px; padding: 16px; display: none; }
+  #walkin label { display: block; margin: 8px 0 4px; }
+  #walkin .actions { margin-top: 12px; display: flex; gap: 8px; }
+  #message { min-height: 1.4em; margin: 8px 0; font-weight: bold; }
+  #message.error { color: #c62828; }
+</style>
+</head>
+<body>
+<header>
+  <select id="event" aria-label="Event"></select>
+  <div id="counts"></div>
+</header>
+<main>
+  <input id="search" type="search" placeholder="Search by name" autocomplete="off" autofocus>
+  <div id="message"></div>
+  <ul id="results"></ul>
+  <button id="show-walkin" class="secondary">Add a walk-in</button>
+  <button id="close-event" class="secondary">Close event</button>
+  <form id="walkin">
+    <label for="preferred">Name</label>
+    <input id="preferred" type="text" autocomplete="off">
+    <label for="legal">Legal name, as on their ID</label>
+    <input id="legal" type="text" autocomplete="off">
+    <div class="actions">
+      <button type="submit">Check in walk-in</button>
+      <button type="button" id="cancel-walkin" class="secondary">Cancel</button>
+    </div>
+  </form>
+</main>
+<script>
+(function () {
+  "use strict";
+  var $ = function (id) { return document.getElementById(id); };
+  var eventID = "";
+  var searchTimer = null;
+
+  function api(method, path, body) {
+    var options = { method: method, headers: {} };
+    if (body !== undefined) {
+      options.headers["Content-Type"] = "application/json";
+      options.body = JSON.stringify(body);
+    }
+    return fetch(path, options).then(function (response) {
+      return response.json().then(function (data) {
+        if (!response.ok) { throw new Error(data.error || response.statusText); }
+        return data;
+      });
+    });
+  }
+
+  function eventPath(suffix) {
+    return "/api/events/" + encodeURIComponent(eventID) + suffix;
+  }
+
+  function say(text, isError) {
+    var message = $("message");
+    message.textContent = text;
+    message.className = isError ? "error" : "";
+  }
+
+  function showCounts(counts) {
+    $("counts").innerHTML = "";
+    var parts = [["In the room", counts.present], ["Checked in", counts.checked_in + " / " + counts.rsvps], ["Walk-ins", counts.walk_ins]];
+    parts.forEach(function (part) {
+      var span = document.createElement("span");
+      span.style.marginLeft = "16px";
+      span.appendChild(document.createTextNode(part[0] + " "));
+      var b = document.createElement("b");
+      b.textContent = part[1];
+      span.appendChild(b);
+      $("counts").appendChild(span);
+    });
+  }
+
+  function present(attendance) {
+    return attendance.check_in_status === "checked-in" || attendance.check_in_status === "walk-in";
+  }
+
+  function showResults(attendances) {
+    var list = $("results");
+    list.innerHTML = "";
+    attendances.forEach(function (attendance) {
+      var person = attendance.attendee;
+      var item = document.createElement("li");
+      if (present(attendance)) { item.className = "present"; }
+      var name = document.createElement("div");
+      name.className = "name";
+      name.textContent = person.preferred_name || person.legal_name;
+      if (person.legal_name && person.legal_name !== person.preferred_name) {
+        var legal = document.createElement("div");
+        legal.className = "legal";
+        legal.textContent = person.legal_name;
+        name.appendChild(legal);
+      }
+      if (!attendance.rsvp && !present(attendance)) {
+        var note = document.createElement("div");
+        note.className = "note";
+        note.textContent = "RSVPed no";
+        name.appendChild(note);
+      }
+      item.appendChild(name);
+      var button = document.createElement("button");
+      if (present(attendance)) {
+        button.textContent = "Undo";
+        button.className = "secondary";
+        button.onclick = function () { undo(attendance, button); };
+      } else {
+        button.textContent = "Check in";
+        button.onclick = function () { checkIn(attendance, button); };
+      }
+      item.appendChild(button);
+      list.appendChild(item);
+    });
+  }
+
+  function search() {
+    if (!eventID) { return; }
+    api("GET", eventPath("/checkins?q=" + encodeURIComponent($("search").value))).then(function (result) {
+      showCounts(result.counts);
+      showResults(result.attendances);
+    }).catch(function (err) { say(err.message, true); });
+  }
+
+  function personName(attendance) {
+    return attendance.attendee.preferred_name || attendance.attendee.legal_name;
+  }
+
+  function checkIn(attendance, button) {
+    button.disabled = true;
+    api("POST", eventPath("/checkins"), { user_id: attendance.attendee.user_id }).then(function (result) {
+      say("Welcome, " + personName(attendance) + "!");
+      showCounts(result.counts);
+      $("search").value = "";
+      search();
+      $("search").focus();
+    }).catch(function (err) { button.disabled = false; say(err.message, true); });
+  }
+
+  function undo(attendance, button) {
+    button.disabled = true;
+    api("DELETE", eventPath("/checkins/" + encodeURIComponent(attendance.attendee.user_id))).then(function (result) {
+      say("Undid check-in for " + personName(attendance));
+      showCounts(result.counts);
+      search();
+    }).catch(function (err) { button.disabled = false; say(err.message, true); });
+  }
+
+  function toggleWalkIn(show) {
+    $("walkin").style.display = show ? "block" : "none";
+    $("show-walkin").style.display = show ? "none" : "";
+    $("close-event").style.display = show ? "none" : "";
+    if (show) {
+      $("preferred").value = $("search").value;
+      $("legal").value = "";
+      $("preferred").focus();
+    }
+  }
+
+  $("close-event").onclick = function () {
+    if (!eventID || !window.confirm("Close check-in? Everyone who RSVPed yes and isn't checked in will be marked a no-show.")) { return; }
+    api("POST", eventPath("/close")).then(function (result) {
+      say("Closed the event, marking " + result.no_shows + " no-shows.");
+      showCounts(result.counts);
+      search();
+    }).catch(function (err) { say(err.message, true); });
+  };
+
+  $("show-walkin").onclick = function () { toggleWalkIn(true); };
+  $("cancel-walkin").onclick = function () { toggleWalkIn(false); };
+  $("walkin").onsubmit = function (e) {
+    e.preventDefault();
+    api("POST", eventPath("/walkins"), { preferred_name: $("preferred").value, legal_name: $("legal").value }).then(function (result) {
+      say("Welcome, " + personName(result.attendances[0]) + "!");
+      showCounts(result.counts);
+      toggleWalkIn(false);
+      $("search").value = "";
+      search();
+    }).catch(function (err) { say(err.message, true); });
+  };
+
+  $("search").oninput = function () {
+    clearTimeout(searchTimer);
+    searchTimer = setTimeout(search, 150);
+  };
+
+  $("event").onchange = function () {
+    eventID = $("event").value;
+    window.location.hash = encodeURIComponent(eventID);
+    say("");
+    search();
+  };
+
+  // Default to the event in the URL, or the one closest to now.
+  api("GET", "/api/events").then(function (events) {
+    var now = Date.now();
+    events.sort(function (a, b) { return new Date(b.time) - new Date(a.time); });
+    var wanted = decodeURIComponent(window.location.hash.slice(1));
+    var closest = null;
+    events.forEach(function (event) {
+      var option = document.createElement("option");
+      option.value = event.id;
+      option.textContent = event.name + " — " + new Date(event.time).toLocaleString();
+      $("event").appendChild(option);
+      if (closest === null || Math.abs(new Date(event.time) - now) < Math.abs(new Date(closest.time) - now)) {
+        closest = event;
+      }
+    });
+    if (events.some(function (event) { return event.id === wanted; })) {
+      $("event").value = wanted;
+    } else if (closest) {
+      $("event").value = closest.id;
+    }
+    eventID = $("event").value;
+    if (!eventID) { say("There are no events yet.", true); }
+    search();
+  }).catch(function (err) { say(err.message, true); });
+
+  // Pick up check-ins made at other kiosks.
+  setInterval(function () {
+    if (document.activeElement !== $("preferred") && document.activeElement !== $("legal")) { search(); }
+  }, 15000);
+})();
+</script>
+</body>
+</html>
+`
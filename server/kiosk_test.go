@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKiosk(t *testing.T) {
+	s := newTestServer()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/kiosk/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	page := w.Body.String()
+	assert.Contains(t, page, "/api/events")
+	assert.Contains(t, page, `eventPath("/close")`)
+	// Nothing may be loaded from elsewhere.
+	for _, external := range []string{"http://", "https://", "//cdn", "<link"} {
+		assert.False(t, strings.Contains(page, external), external)
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/kiosk/", w.Header().Get("Location"))
+}
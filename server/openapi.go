@@ -0,0 +1,306 @@
+package server
+
+// openAPIDocument describes the API. It is served from the binary at
+// /api/openapi.json and must be kept in step with the handlers.
+const openAPIDocument = `{
+  "openapi": "3.0.2",
+  "info": {
+    "title": "Community Attendance API",
+    "version": "1.0.0",
+    "description": "Events, attendees and attendances from the attendance storage."
+  },
+  "paths": {
+    "/api/events": {
+      "get": {
+        "summary": "List events",
+        "responses": {
+          "200": {"description": "Every event", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Event"}}}}}
+        }
+      },
+      "post": {
+        "summary": "Create an event",
+        "description": "A random ID is assigned when none is given.",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+        "responses": {
+          "201": {"description": "The created event", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "409": {"description": "An event with the ID already exists", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+        }
+      }
+    },
+    "/api/events/{eventId}": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}],
+      "get": {
+        "summary": "Get an event",
+        "responses": {
+          "200": {"description": "The event", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "put": {
+        "summary": "Update an event",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+        "responses": {
+          "200": {"description": "The updated event", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "delete": {
+        "summary": "Delete an event with its attendances and import history",
+        "responses": {
+          "204": {"description": "Deleted"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/events/{eventId}/attendances": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}],
+      "get": {
+        "summary": "List an event's attendances",
+        "responses": {
+          "200": {"description": "The event's attendances", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Attendance"}}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/events/{eventId}/checkins": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}],
+      "get": {
+        "summary": "Search an event's RSVP list for checking in",
+        "description": "People who RSVPed no are only listed when the search matches them.",
+        "parameters": [{"name": "q", "in": "query", "schema": {"type": "string"}, "description": "Fuzzy search on preferred or legal name"}],
+        "responses": {
+          "200": {"description": "The headcount and the best matches", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CheckInResult"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "post": {
+        "summary": "Check someone in",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CheckInRequest"}}}},
+        "responses": {
+          "200": {"description": "The headcount and the checked in attendance", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CheckInResult"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/events/{eventId}/checkins/{userId}": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}, {"$ref": "#/components/parameters/UserID"}],
+      "delete": {
+        "summary": "Undo a check-in",
+        "responses": {
+          "200": {"description": "The headcount and the attendance", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CheckInResult"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/events/{eventId}/walkins": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}],
+      "post": {
+        "summary": "Check in someone with no stored record as a new attendee",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CheckInRequest"}}}},
+        "responses": {
+          "201": {"description": "The headcount and the walk-in's attendance", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CheckInResult"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/events/{eventId}/close": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}],
+      "post": {
+        "summary": "End check-in, marking everyone who RSVPed yes and wasn't checked in as a no-show",
+        "responses": {
+          "200": {"description": "The headcount and how many were marked", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CloseResult"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/events/{eventId}/import": {
+      "parameters": [{"$ref": "#/components/parameters/EventID"}],
+      "post": {
+        "summary": "Import an attendee export into an event",
+        "description": "The whole file is saved or nothing is. RSVPs saved by earlier imports that are missing from the file are marked cancelled, and the file's name and SHA-256 hash are added to the event's import history.",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["file"],
+                "properties": {
+                  "file": {"type": "string", "format": "binary"},
+                  "format": {"type": "string", "description": "The file format; detected when empty"},
+                  "sheet": {"type": "string", "description": "The name or 1-based position of the worksheet in a workbook"},
+                  "legal_name_question": {"type": "string", "description": "Text identifying the legal name column"}
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {"description": "What the import changed", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ImportSummary"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/attendees": {
+      "get": {
+        "summary": "List attendees",
+        "responses": {
+          "200": {"description": "Every attendee", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Attendee"}}}}}
+        }
+      }
+    },
+    "/api/attendees/{userId}": {
+      "parameters": [{"$ref": "#/components/parameters/UserID"}],
+      "get": {
+        "summary": "Get an attendee",
+        "responses": {
+          "200": {"description": "The attendee", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Attendee"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "put": {
+        "summary": "Create or update an attendee",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Attendee"}}}},
+        "responses": {
+          "200": {"description": "The updated attendee", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Attendee"}}}},
+          "201": {"description": "The created attendee", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Attendee"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      },
+      "delete": {
+        "summary": "Delete an attendee with their attendances",
+        "responses": {
+          "204": {"description": "Deleted"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/attendees/{userId}/attendances": {
+      "parameters": [{"$ref": "#/components/parameters/UserID"}],
+      "get": {
+        "summary": "List an attendee's attendances",
+        "responses": {
+          "200": {"description": "The attendee's attendances", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Attendance"}}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "EventID": {"name": "eventId", "in": "path", "required": true, "schema": {"type": "string"}},
+      "UserID": {"name": "userId", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "BadRequest": {"description": "The request was malformed", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}},
+      "NotFound": {"description": "No such event or attendee", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "Event": {
+        "type": "object",
+        "required": ["name", "time"],
+        "properties": {
+          "id": {"type": "string"},
+          "name": {"type": "string"},
+          "time": {"type": "string", "format": "date-time", "description": "When the event starts, given with the UTC offset of its time zone"},
+          "venue_name": {"type": "string"},
+          "venue_address": {"type": "string"},
+          "capacity": {"type": "integer", "minimum": 0, "description": "How many people the venue holds"},
+          "group": {"type": "string", "description": "The organizing group"},
+          "series_id": {"type": "string", "description": "Ties together the events of a recurring series"},
+          "duration_minutes": {"type": "integer", "minimum": 0},
+          "time_zone": {"type": "string", "description": "The IANA name of the event's time zone, such as America/Los_Angeles"},
+          "source_id": {"type": "string", "description": "The event's ID in the system it was imported from"}
+        }
+      },
+      "Attendee": {
+        "type": "object",
+        "properties": {
+          "user_id": {"type": "string", "readOnly": true},
+          "preferred_name": {"type": "string"},
+          "legal_name": {"type": "string"},
+          "profile_url": {"type": "string", "format": "uri"},
+          "is_host": {"type": "boolean"},
+          "joined_date": {"type": "string", "format": "date-time"},
+          "email": {"type": "string", "format": "email"}
+        }
+      },
+      "Attendance": {
+        "type": "object",
+        "properties": {
+          "event_id": {"type": "string"},
+          "attendee": {"$ref": "#/components/schemas/Attendee"},
+          "rsvp": {"type": "boolean"},
+          "rsvp_time": {"type": "string", "format": "date-time"},
+          "check_in_status": {"type": "string", "enum": ["checked-in", "no-show", "walk-in"]},
+          "check_in_time": {"type": "string", "format": "date-time"},
+          "checked_in_by": {"type": "string"},
+          "cancelled": {"type": "boolean", "description": "Whether the RSVP disappeared from a later import"}
+        }
+      },
+      "Counts": {
+        "type": "object",
+        "properties": {
+          "rsvps": {"type": "integer"},
+          "checked_in": {"type": "integer"},
+          "walk_ins": {"type": "integer"},
+          "present": {"type": "integer"}
+        }
+      },
+      "CheckInRequest": {
+        "type": "object",
+        "properties": {
+          "user_id": {"type": "string", "description": "Who to check in"},
+          "preferred_name": {"type": "string", "description": "A walk-in's name"},
+          "legal_name": {"type": "string", "description": "A walk-in's legal name"},
+          "by": {"type": "string", "description": "Who made the check-in; defaults to kiosk"}
+        }
+      },
+      "CheckInResult": {
+        "type": "object",
+        "properties": {
+          "counts": {"$ref": "#/components/schemas/Counts"},
+          "attendances": {"type": "array", "items": {"$ref": "#/components/schemas/Attendance"}}
+        }
+      },
+      "CloseResult": {
+        "type": "object",
+        "properties": {
+          "counts": {"$ref": "#/components/schemas/Counts"},
+          "no_shows": {"type": "integer"}
+        }
+      },
+      "ImportCounts": {
+        "type": "object",
+        "properties": {
+          "created": {"type": "integer"},
+          "updated": {"type": "integer"},
+          "unchanged": {"type": "integer"}
+        }
+      },
+      "ImportSummary": {
+        "type": "object",
+        "properties": {
+          "records": {"type": "integer"},
+          "event": {"type": "string", "enum": ["inserted", "updated", "unchanged"]},
+          "attendees": {"$ref": "#/components/schemas/ImportCounts"},
+          "attendances": {"$ref": "#/components/schemas/ImportCounts"},
+          "merged": {"type": "integer", "description": "Records saved against the attendee their user ID was merged into"},
+          "cancelled": {"type": "integer", "description": "RSVPs from earlier imports missing from this one"}
+        }
+      },
+      "Error": {
+        "type": "object",
+        "properties": {
+          "error": {"type": "string"}
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	var document struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	assert.Equal(t, http.StatusOK, doJSON(t, newTestServer(), http.MethodGet, "/api/openapi.json", nil, &document))
+	assert.Equal(t, "3.0.2", document.OpenAPI)
+	for _, path := range []string{
+		"/api/events", "/api/events/{eventId}", "/api/events/{eventId}/attendances", "/api/events/{eventId}/import",
+		"/api/events/{eventId}/checkins", "/api/events/{eventId}/checkins/{userId}", "/api/events/{eventId}/walkins",
+		"/api/events/{eventId}/close",
+		"/api/attendees", "/api/attendees/{userId}", "/api/attendees/{userId}/attendances",
+	} {
+		assert.Contains(t, document.Paths, path)
+	}
+}
@@ -0,0 +1,113 @@
+// Package server exposes the storage layer as a JSON REST API.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+// maxUploadSize bounds import uploads; attendee exports are small.
+const maxUploadSize = 32 << 20
+
+var log = logrus.StandardLogger()
+
+// Server serves the REST API for a store.
+type Server struct {
+	store    storage.Store
+	location *time.Location
+	mux      *http.ServeMux
+}
+
+// New creates a server for store. location is the time zone of events that
+// don't name one. The caller still owns the store and closes it once the
+// server is done.
+func New(store storage.Store, location *time.Location) *Server {
+	s := &Server{store: store, location: location, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/api/openapi.json", s.openAPI)
+	s.mux.HandleFunc("/api/events", s.events)
+	s.mux.HandleFunc("/api/events/", s.event)
+	s.mux.HandleFunc("/api/attendees", s.attendees)
+	s.mux.HandleFunc("/api/attendees/", s.attendee)
+	s.mux.HandleFunc("/kiosk/", s.kiosk)
+	s.mux.HandleFunc("/", s.root)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// pathParts splits the path below prefix into its unescaped segments.
+func pathParts(r *http.Request, prefix string) []string {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if rest == "" {
+		return nil
+	}
+	return strings.Split(rest, "/")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("error writing response")
+	}
+}
+
+// statusForError maps storage errors to HTTP statuses: missing entries are
+// 404s and anything unexpected is a 500.
+func statusForError(err error) int {
+	switch errors.Cause(err) {
+	case storage.ErrNoEntryWithUserID, storage.ErrNoEntryWithEventID, storage.ErrNoAttendanceEntry:
+		return http.StatusNotFound
+	}
+	if _, ok := errors.Cause(err).(badRequest); ok {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// badRequest marks an error as the client's fault.
+type badRequest struct {
+	error
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := statusForError(err)
+	if status == http.StatusInternalServerError {
+		log.WithError(err).Error("error handling request")
+	}
+	writeJSON(w, status, &errorJSON{Error: err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, &errorJSON{Error: "method not allowed"})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, &errorJSON{Error: "not found"})
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest{errors.Wrap(err, "error decoding request body")}
+	}
+	return nil
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPIDocument))
+}
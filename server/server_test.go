@@ -0,0 +1,53 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	memory "github.com/alexthemitchell/community-attendance/storage/memory"
+)
+
+func newTestServer() *Server {
+	return New(memory.NewMemoryStorage(), time.UTC)
+}
+
+// do sends a request to s and decodes the JSON response into v, if given.
+func do(t *testing.T, s *Server, method, path string, body io.Reader, contentType string, v interface{}) int {
+	r := httptest.NewRequest(method, path, body)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if v != nil {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), v), w.Body.String())
+	}
+	return w.Code
+}
+
+func doJSON(t *testing.T, s *Server, method, path string, body interface{}, v interface{}) int {
+	var b bytes.Buffer
+	if body != nil {
+		assert.NoError(t, json.NewEncoder(&b).Encode(body))
+	}
+	return do(t, s, method, path, &b, "application/json", v)
+}
+
+func TestNotFoundAndMethods(t *testing.T) {
+	s := newTestServer()
+	var e errorJSON
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodGet, "/api/events/nope", nil, &e))
+	assert.Contains(t, e.Error, "event")
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodGet, "/api/attendees/nope", nil, &e))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodGet, "/api/events/nope/attendances", nil, &e))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, s, http.MethodGet, "/api/events/nope/elsewhere", nil, &e))
+	assert.Equal(t, http.StatusMethodNotAllowed, doJSON(t, s, http.MethodPatch, "/api/events", nil, &e))
+	assert.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/api/events", bytes.NewBufferString("{"), "application/json", &e))
+}
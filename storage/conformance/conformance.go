@@ -0,0 +1,687 @@
+// Package conformance holds the behavioral test suite every storage backend
+// must pass, so that backends can't drift apart.
+package conformance
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	storage "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+// OpenFunc returns a new, empty store and a function that releases it.
+type OpenFunc func(t *testing.T) (storage.Store, func())
+
+// Run runs the whole suite against stores returned by open. Each test gets
+// its own store.
+func Run(t *testing.T, open OpenFunc) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s storage.Store)
+	}{
+		{"AttendeeCRUD", testAttendeeCRUD},
+		{"AttendeeWithoutOptionalFields", testAttendeeWithoutOptionalFields},
+		{"EventCRUD", testEventCRUD},
+		{"EventDetails", testEventDetails},
+		{"TimesInOtherZones", testTimesInOtherZones},
+		{"AttendanceCRUD", testAttendanceCRUD},
+		{"AttendanceRequiresAttendeeAndEvent", testAttendanceRequiresAttendeeAndEvent},
+		{"AttendanceCheckIn", testAttendanceCheckIn},
+		{"AttendanceCancelled", testAttendanceCancelled},
+		{"AttendeeEmail", testAttendeeEmail},
+		{"Aliases", testAliases},
+		{"Imports", testImports},
+		{"DeleteRemovesDependentRows", testDeleteRemovesDependentRows},
+		{"QueryEvents", testQueryEvents},
+		{"QueryAttendees", testQueryAttendees},
+		{"NonASCIINames", testNonASCIINames},
+		{"TransactionCommit", testTransactionCommit},
+		{"TransactionRollback", testTransactionRollback},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s, cleanup := open(t)
+			defer cleanup()
+			tc.test(t, s)
+		})
+	}
+}
+
+var (
+	eventTime = time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	joinDate  = time.Date(2017, time.July, 8, 0, 0, 0, 0, time.UTC)
+	rsvpTime  = time.Date(2019, time.February, 18, 17, 56, 0, 0, time.UTC)
+)
+
+func profile(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	assert.NoError(t, err)
+	return u
+}
+
+func testAttendee(t *testing.T, userID, legalName string) *models.Attendee {
+	return models.NewAttendee("Preferred "+userID, legalName, userID, profile(t, "https://www.meetup.com/members/"+userID+"/"), &joinDate, false)
+}
+
+func testEvent(id, name string) *models.Event {
+	return models.NewEvent(name, id, &eventTime)
+}
+
+func assertUpsert(t *testing.T, expected storage.UpsertResult, result storage.UpsertResult, err error) {
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func testAttendeeCRUD(t *testing.T, s storage.Store) {
+	_, err := s.FetchAttendee("user 1")
+	assert.Equal(t, storage.ErrNoEntryWithUserID, errors.Cause(err))
+
+	result, err := s.UpsertAttendee(testAttendee(t, "user 1", "Alex Mitchell"))
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertAttendee(testAttendee(t, "user 1", "Alex Mitchell"))
+	assertUpsert(t, storage.Unchanged, result, err)
+	result, err = s.UpsertAttendee(testAttendee(t, "user 1", "Alexander Mitchell"))
+	assertUpsert(t, storage.Updated, result, err)
+	result, err = s.UpsertAttendee(testAttendee(t, "user 2", "The Dubester"))
+	assertUpsert(t, storage.Inserted, result, err)
+
+	fetched, err := s.FetchAttendee("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Preferred user 1", fetched.PreferredName())
+	assert.Equal(t, "Alexander Mitchell", fetched.LegalName())
+	assert.Equal(t, "https://www.meetup.com/members/user%201/", fetched.ProfileURL().String())
+	assert.Equal(t, joinDate, *fetched.JoinedDate())
+	assert.False(t, fetched.IsHost())
+
+	count, err := s.CountAttendees()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+
+	all, err := s.GetAllAttendees()
+	assert.NoError(t, err)
+	var ids []string
+	for _, a := range all {
+		ids = append(ids, a.UserID())
+	}
+	sort.Strings(ids)
+	assert.Equal(t, []string{"user 1", "user 2"}, ids)
+
+	assert.NoError(t, s.DeleteAttendee("user 1"))
+	assert.NoError(t, s.DeleteAttendee("user 1"))
+	_, err = s.FetchAttendee("user 1")
+	assert.Equal(t, storage.ErrNoEntryWithUserID, errors.Cause(err))
+	count, err = s.CountAttendees()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), count)
+}
+
+func testAttendeeWithoutOptionalFields(t *testing.T, s storage.Store) {
+	result, err := s.UpsertAttendee(models.NewAttendee("Walk-in", "", "user 3", nil, nil, true))
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertAttendee(models.NewAttendee("Walk-in", "", "user 3", nil, nil, true))
+	assertUpsert(t, storage.Unchanged, result, err)
+
+	fetched, err := s.FetchAttendee("user 3")
+	assert.NoError(t, err)
+	assert.Nil(t, fetched.JoinedDate())
+	assert.Equal(t, "", fetched.ProfileURL().String())
+	assert.True(t, fetched.IsHost())
+}
+
+func testEventCRUD(t *testing.T, s storage.Store) {
+	_, err := s.FetchEvent("event-1")
+	assert.Equal(t, storage.ErrNoEntryWithEventID, errors.Cause(err))
+
+	result, err := s.UpsertEvent(testEvent("event-1", "Hack Night"))
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertEvent(testEvent("event-1", "Hack Night"))
+	assertUpsert(t, storage.Unchanged, result, err)
+	result, err = s.UpsertEvent(testEvent("event-1", "Hack Night #2"))
+	assertUpsert(t, storage.Updated, result, err)
+
+	fetched, err := s.FetchEvent("event-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hack Night #2", fetched.Name())
+	assert.Equal(t, eventTime, *fetched.Time())
+
+	all, err := s.GetAllEvents()
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	assert.NoError(t, s.DeleteEvent("event-1"))
+	count, err := s.CountEvents()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), count)
+	_, err = s.FetchEvent("event-1")
+	assert.Equal(t, storage.ErrNoEntryWithEventID, errors.Cause(err))
+}
+
+func testTimesInOtherZones(t *testing.T, s storage.Store) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 6:30 PM on each side of the start of daylight saving time.
+	beforeDST := time.Date(2019, time.March, 9, 18, 30, 0, 0, losAngeles)
+	afterDST := time.Date(2019, time.March, 14, 18, 30, 0, 0, losAngeles)
+	before := models.NewEvent("Hack Night", "event-1", &beforeDST)
+	after := models.NewEvent("Hack Night", "event-2", &afterDST)
+	for _, event := range []*models.Event{before, after} {
+		result, err := s.UpsertEvent(event)
+		assertUpsert(t, storage.Inserted, result, err)
+	}
+	// The same instant in another zone is unchanged.
+	utc := beforeDST.UTC()
+	result, err := s.UpsertEvent(models.NewEvent("Hack Night", "event-1", &utc))
+	assertUpsert(t, storage.Unchanged, result, err)
+
+	fetched, err := s.FetchEvent("event-1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 10, 2, 30, 0, 0, time.UTC), *fetched.Time())
+	fetched, err = s.FetchEvent("event-2")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.March, 15, 1, 30, 0, 0, time.UTC), *fetched.Time())
+
+	from := time.Date(2019, time.March, 9, 19, 0, 0, 0, losAngeles)
+	events, err := s.QueryEvents(storage.EventQuery{From: &from})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-2"}, eventIDs(events))
+
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	_, err = s.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	rsvpAt := time.Date(2019, time.March, 12, 9, 15, 0, 0, losAngeles)
+	_, err = s.UpsertAttendance(models.NewAttendance(attendee, after, true, &rsvpAt).WithCheckIn(models.CheckedIn, &afterDST, "door"))
+	assert.NoError(t, err)
+	attendance, err := s.FetchAttendance("event-2", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, rsvpAt.UTC(), *attendance.RSVPTime())
+	assert.Equal(t, afterDST.UTC(), *attendance.CheckInTime())
+}
+
+func testEventDetails(t *testing.T, s storage.Store) {
+	details := models.EventDetails{
+		VenueName:    "The Library",
+		VenueAddress: "1000 4th Ave, Seattle, WA",
+		Capacity:     40,
+		Group:        "Community Hack Night",
+		SeriesID:     "weekly",
+		Duration:     2*time.Hour + 30*time.Minute,
+		TimeZone:     "America/Los_Angeles",
+		SourceID:     "259874361",
+	}
+	event := testEvent("event-1", "Hack Night").WithDetails(details)
+	result, err := s.UpsertEvent(event)
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertEvent(event)
+	assertUpsert(t, storage.Unchanged, result, err)
+
+	fetched, err := s.FetchEvent("event-1")
+	assert.NoError(t, err)
+	assert.Equal(t, details, fetched.Details())
+
+	details.Capacity = 60
+	result, err = s.UpsertEvent(event.WithDetails(details))
+	assertUpsert(t, storage.Updated, result, err)
+
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	_, err = s.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	_, err = s.UpsertAttendance(models.NewAttendance(attendee, event, true, nil))
+	assert.NoError(t, err)
+	attendance, err := s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, details, attendance.Event().Details())
+}
+
+func testAttendanceCRUD(t *testing.T, s storage.Store) {
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	event := testEvent("event-1", "Hack Night")
+	other := testEvent("event-2", "Hack Night #2")
+	for _, e := range []*models.Event{event, other} {
+		_, err := s.UpsertEvent(e)
+		assert.NoError(t, err)
+	}
+	_, err := s.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+
+	_, err = s.FetchAttendance("event-1", "user 1")
+	assert.Equal(t, storage.ErrNoAttendanceEntry, errors.Cause(err))
+
+	result, err := s.UpsertAttendance(models.NewAttendance(attendee, event, true, &rsvpTime))
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertAttendance(models.NewAttendance(attendee, event, true, &rsvpTime))
+	assertUpsert(t, storage.Unchanged, result, err)
+	result, err = s.UpsertAttendance(models.NewAttendance(attendee, other, false, nil))
+	assertUpsert(t, storage.Inserted, result, err)
+
+	fetched, err := s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.True(t, fetched.RSVP())
+	assert.Equal(t, rsvpTime, *fetched.RSVPTime())
+	assert.Equal(t, "Hack Night", fetched.Event().Name())
+	assert.Equal(t, "Alex Mitchell", fetched.Attendee().LegalName())
+
+	fetched, err = s.FetchAttendance("event-2", "user 1")
+	assert.NoError(t, err)
+	assert.False(t, fetched.RSVP())
+	assert.Nil(t, fetched.RSVPTime())
+
+	byEvent, err := s.GetAttendancesForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Len(t, byEvent, 1)
+	byAttendee, err := s.GetAttendancesForAttendee("user 1")
+	assert.NoError(t, err)
+	assert.Len(t, byAttendee, 2)
+
+	count, err := s.CountAttendances()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+
+	assert.NoError(t, s.DeleteAttendance("event-1", "user 1"))
+	_, err = s.FetchAttendance("event-1", "user 1")
+	assert.Equal(t, storage.ErrNoAttendanceEntry, errors.Cause(err))
+}
+
+func testAttendanceRequiresAttendeeAndEvent(t *testing.T, s storage.Store) {
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	event := testEvent("event-1", "Hack Night")
+	_, err := s.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = s.UpsertAttendance(models.NewAttendance(attendee, event, true, &rsvpTime))
+	assert.NoError(t, err)
+
+	// Without a stored attendee the attendance can't be joined and is hidden.
+	_, err = s.FetchAttendance("event-1", "user 1")
+	assert.Equal(t, storage.ErrNoAttendanceEntry, errors.Cause(err))
+	byEvent, err := s.GetAttendancesForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Len(t, byEvent, 0)
+
+	_, err = s.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	byEvent, err = s.GetAttendancesForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Len(t, byEvent, 1)
+}
+
+func testAttendanceCheckIn(t *testing.T, s storage.Store) {
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	event := testEvent("event-1", "Hack Night")
+	_, err := s.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = s.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	rsvp := models.NewAttendance(attendee, event, true, &rsvpTime)
+	_, err = s.UpsertAttendance(rsvp)
+	assert.NoError(t, err)
+
+	fetched, err := s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NotCheckedIn, fetched.CheckInStatus())
+	assert.Nil(t, fetched.CheckInTime())
+
+	checkInTime := rsvpTime.Add(time.Hour)
+	result, err := s.UpsertAttendance(rsvp.WithCheckIn(models.CheckedIn, &checkInTime, "door volunteer"))
+	assertUpsert(t, storage.Updated, result, err)
+	result, err = s.UpsertAttendance(rsvp.WithCheckIn(models.CheckedIn, &checkInTime, "door volunteer"))
+	assertUpsert(t, storage.Unchanged, result, err)
+
+	fetched, err = s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CheckedIn, fetched.CheckInStatus())
+	assert.Equal(t, checkInTime, *fetched.CheckInTime())
+	assert.Equal(t, "door volunteer", fetched.CheckedInBy())
+	assert.True(t, fetched.RSVP())
+}
+
+func testAttendanceCancelled(t *testing.T, s storage.Store) {
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	event := testEvent("event-1", "Hack Night")
+	_, err := s.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = s.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	rsvp := models.NewAttendance(attendee, event, true, &rsvpTime)
+	result, err := s.UpsertAttendance(rsvp)
+	assertUpsert(t, storage.Inserted, result, err)
+	fetched, err := s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.False(t, fetched.Cancelled())
+
+	result, err = s.UpsertAttendance(rsvp.WithCancelled(true))
+	assertUpsert(t, storage.Updated, result, err)
+	result, err = s.UpsertAttendance(rsvp.WithCancelled(true))
+	assertUpsert(t, storage.Unchanged, result, err)
+	fetched, err = s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.True(t, fetched.Cancelled())
+}
+
+func testAttendeeEmail(t *testing.T, s storage.Store) {
+	attendee := testAttendee(t, "user 1", "Alex Mitchell")
+	result, err := s.UpsertAttendee(attendee)
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertAttendee(attendee.WithEmail("alex@example.com"))
+	assertUpsert(t, storage.Updated, result, err)
+	result, err = s.UpsertAttendee(attendee.WithEmail("alex@example.com"))
+	assertUpsert(t, storage.Unchanged, result, err)
+
+	fetched, err := s.FetchAttendee("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alex@example.com", fetched.Email())
+
+	event := testEvent("event-1", "Hack Night")
+	_, err = s.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = s.UpsertAttendance(models.NewAttendance(fetched, event, true, nil))
+	assert.NoError(t, err)
+	attendance, err := s.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alex@example.com", attendance.Attendee().Email())
+}
+
+func testAliases(t *testing.T, s storage.Store) {
+	resolved, err := s.ResolveUserID("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user 1", resolved)
+	aliases, err := s.GetAliases("user 2")
+	assert.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	result, err := s.UpsertAlias("user 1", "user 2")
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertAlias("user 1", "user 2")
+	assertUpsert(t, storage.Unchanged, result, err)
+	result, err = s.UpsertAlias("user 0", "user 3")
+	assertUpsert(t, storage.Inserted, result, err)
+	result, err = s.UpsertAlias("user 0", "user 2")
+	assertUpsert(t, storage.Updated, result, err)
+
+	resolved, err = s.ResolveUserID("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user 2", resolved)
+	aliases, err = s.GetAliases("user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user 0", "user 1"}, aliases)
+
+	_, err = s.UpsertAlias("user 2", "user 2")
+	assert.Error(t, err)
+
+	err = s.WithTx(func(tx storage.Store) error {
+		if _, err := tx.UpsertAlias("user 4", "user 2"); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+	resolved, err = s.ResolveUserID("user 4")
+	assert.NoError(t, err)
+	assert.Equal(t, "user 4", resolved)
+}
+
+func eventIDs(events []*models.Event) []string {
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.ID())
+	}
+	return ids
+}
+
+func userIDs(attendees []*models.Attendee) []string {
+	var ids []string
+	for _, a := range attendees {
+		ids = append(ids, a.UserID())
+	}
+	return ids
+}
+
+func testDeleteRemovesDependentRows(t *testing.T, s storage.Store) {
+	event := testEvent("event-1", "Hack Night")
+	attendees := []*models.Attendee{
+		models.NewAttendee("Alex", "", "user 1", nil, nil, false),
+		models.NewAttendee("Bo", "", "user 2", nil, nil, false),
+	}
+	save := func() {
+		_, err := s.UpsertEvent(event)
+		assert.NoError(t, err)
+		for _, attendee := range attendees {
+			_, err := s.UpsertAttendee(attendee)
+			assert.NoError(t, err)
+		}
+	}
+	save()
+	for _, attendee := range attendees {
+		_, err := s.UpsertAttendance(models.NewAttendance(attendee, event, true, nil))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, s.RecordImport(models.NewImport("event-1", "rsvps.tsv", "abc123", eventTime, 2)))
+
+	assert.NoError(t, s.DeleteAttendee("user 1"))
+	count, err := s.CountAttendances()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), count)
+
+	assert.NoError(t, s.DeleteEvent("event-1"))
+	count, err = s.CountAttendances()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), count)
+	imports, err := s.GetImportsForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Empty(t, imports)
+
+	// Nothing comes back when the IDs are used again.
+	save()
+	attendances, err := s.GetAttendancesForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Empty(t, attendances)
+	attendances, err = s.GetAttendancesForAttendee("user 1")
+	assert.NoError(t, err)
+	assert.Empty(t, attendances)
+}
+
+func testImports(t *testing.T, s storage.Store) {
+	imports, err := s.GetImportsForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Empty(t, imports)
+
+	importedAt := time.Date(2019, time.March, 15, 9, 30, 15, 500, time.UTC)
+	earlier := importedAt.Add(-time.Hour)
+	assert.NoError(t, s.RecordImport(models.NewImport("event-1", "rsvps.tsv", "abc123", importedAt, 12)))
+	assert.NoError(t, s.RecordImport(models.NewImport("event-2", "other.tsv", "def456", importedAt, 3)))
+	assert.NoError(t, s.RecordImport(models.NewImport("event-1", "rsvps.xlsx", "789abc", earlier, 10)))
+
+	imports, err = s.GetImportsForEvent("event-1")
+	assert.NoError(t, err)
+	if assert.Len(t, imports, 2) {
+		assert.Equal(t, "rsvps.xlsx", imports[0].FileName())
+		last := imports[1]
+		assert.Equal(t, "event-1", last.EventID())
+		assert.Equal(t, "rsvps.tsv", last.FileName())
+		assert.Equal(t, "abc123", last.SHA256())
+		assert.Equal(t, importedAt.Truncate(time.Second), last.ImportedAt())
+		assert.Equal(t, 12, last.Records())
+	}
+}
+
+func testQueryEvents(t *testing.T, s storage.Store) {
+	for i, name := range []string{"Hack Night", "Board Games", "hack night 100%", "Picnic"} {
+		when := eventTime.AddDate(0, i, 0)
+		event := models.NewEvent(name, fmt.Sprintf("event-%d", i+1), &when).
+			WithDetails(models.EventDetails{SourceID: fmt.Sprintf("meetup-%d", i%2)})
+		_, err := s.UpsertEvent(event)
+		assert.NoError(t, err)
+	}
+	query := func(q storage.EventQuery) []string {
+		events, err := s.QueryEvents(q)
+		assert.NoError(t, err)
+		return eventIDs(events)
+	}
+
+	assert.Equal(t, []string{"event-1", "event-2", "event-3", "event-4"}, query(storage.EventQuery{}))
+	assert.Equal(t, []string{"event-4", "event-3", "event-2", "event-1"}, query(storage.EventQuery{Sort: "-time"}))
+	assert.Equal(t, []string{"event-2", "event-1", "event-3", "event-4"}, query(storage.EventQuery{Sort: "name"}))
+	assert.Equal(t, []string{"event-1", "event-3"}, query(storage.EventQuery{NameContains: "HACK"}))
+	assert.Equal(t, []string{"event-3"}, query(storage.EventQuery{NameContains: "0%"}))
+	assert.Empty(t, query(storage.EventQuery{NameContains: "_"}))
+	assert.Equal(t, []string{"event-2", "event-4"}, query(storage.EventQuery{SourceID: "meetup-1"}))
+	assert.Empty(t, query(storage.EventQuery{SourceID: "meetup"}))
+
+	from, to := eventTime.AddDate(0, 1, 0), eventTime.AddDate(0, 3, 0)
+	assert.Equal(t, []string{"event-2", "event-3"}, query(storage.EventQuery{From: &from, To: &to}))
+	assert.Equal(t, []string{"event-2", "event-3", "event-4"}, query(storage.EventQuery{From: &from}))
+
+	assert.Equal(t, []string{"event-2", "event-3"}, query(storage.EventQuery{Limit: 2, Offset: 1}))
+	assert.Equal(t, []string{"event-3", "event-4"}, query(storage.EventQuery{Offset: 2}))
+	assert.Equal(t, []string{"event-1"}, query(storage.EventQuery{Limit: 1}))
+	assert.Empty(t, query(storage.EventQuery{Offset: 10}))
+
+	_, err := s.QueryEvents(storage.EventQuery{Sort: "size"})
+	assert.Error(t, err)
+}
+
+func testQueryAttendees(t *testing.T, s storage.Store) {
+	event := testEvent("event-1", "Hack Night")
+	_, err := s.UpsertEvent(event)
+	assert.NoError(t, err)
+	later := joinDate.AddDate(1, 0, 0)
+	attendees := []*models.Attendee{
+		models.NewAttendee("carol", "Carol Jones", "user 1", nil, &later, false),
+		models.NewAttendee("Alex", "Alex Mitchell", "user 2", nil, &joinDate, true),
+		models.NewAttendee("Bo", "Zed Bo", "user 3", nil, nil, false),
+		models.NewAttendee("Dana", "Dana Lee", "user 4", nil, &joinDate, false),
+	}
+	for _, a := range attendees {
+		_, err := s.UpsertAttendee(a)
+		assert.NoError(t, err)
+	}
+	noShow := models.NewAttendance(attendees[0], event, true, nil).WithCheckIn(models.NoShow, nil, "door")
+	for _, a := range []*models.Attendance{
+		models.NewAttendance(attendees[1], event, true, nil),
+		models.NewAttendance(attendees[2], event, false, nil).WithCheckIn(models.WalkIn, nil, "door"),
+		models.NewAttendance(attendees[3], event, false, nil),
+		noShow,
+	} {
+		_, err := s.UpsertAttendance(a)
+		assert.NoError(t, err)
+	}
+	query := func(q storage.AttendeeQuery) []string {
+		attendees, err := s.QueryAttendees(q)
+		assert.NoError(t, err)
+		return userIDs(attendees)
+	}
+
+	assert.Equal(t, []string{"user 2", "user 3", "user 1", "user 4"}, query(storage.AttendeeQuery{}))
+	assert.Equal(t, []string{"user 2", "user 1", "user 4", "user 3"}, query(storage.AttendeeQuery{Sort: "legal-name"}))
+	assert.Equal(t, []string{"user 2", "user 4", "user 1", "user 3"}, query(storage.AttendeeQuery{Sort: "joined"}))
+	assert.Equal(t, []string{"user 1", "user 4", "user 2", "user 3"}, query(storage.AttendeeQuery{Sort: "-joined"}))
+	assert.Equal(t, []string{"user 4", "user 3", "user 2", "user 1"}, query(storage.AttendeeQuery{Sort: "-user-id"}))
+
+	host, notHost := true, false
+	assert.Equal(t, []string{"user 2"}, query(storage.AttendeeQuery{IsHost: &host}))
+	assert.Equal(t, []string{"user 3", "user 1", "user 4"}, query(storage.AttendeeQuery{IsHost: &notHost}))
+
+	to := joinDate.AddDate(0, 0, 1)
+	assert.Equal(t, []string{"user 2", "user 4"}, query(storage.AttendeeQuery{JoinedTo: &to}))
+	assert.Equal(t, []string{"user 1"}, query(storage.AttendeeQuery{JoinedFrom: &to}))
+
+	// Check-ins were taken at event-1, so user 2's yes RSVP alone doesn't
+	// count; at event-2 nobody was checked in and it does.
+	assert.Equal(t, []string{"user 3"}, query(storage.AttendeeQuery{AttendedEventID: "event-1"}))
+	assert.Empty(t, query(storage.AttendeeQuery{AttendedEventID: "event-2"}))
+	unchecked := testEvent("event-2", "Board Games")
+	_, err = s.UpsertEvent(unchecked)
+	assert.NoError(t, err)
+	for _, a := range []*models.Attendance{
+		models.NewAttendance(attendees[1], unchecked, true, nil),
+		models.NewAttendance(attendees[3], unchecked, false, nil),
+	} {
+		_, err := s.UpsertAttendance(a)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"user 2"}, query(storage.AttendeeQuery{AttendedEventID: "event-2"}))
+
+	assert.Equal(t, []string{"user 3", "user 1"}, query(storage.AttendeeQuery{Limit: 2, Offset: 1}))
+	assert.Equal(t, []string{"user 4"}, query(storage.AttendeeQuery{IsHost: &notHost, Offset: 2}))
+
+	_, err = s.QueryAttendees(storage.AttendeeQuery{Limit: -1})
+	assert.Error(t, err)
+}
+
+// testNonASCIINames checks that every backend folds case beyond ASCII the
+// way strings.ToLower does, when searching and when sorting.
+func testNonASCIINames(t *testing.T, s storage.Store) {
+	for _, event := range []*models.Event{testEvent("event-1", "Éclair Social"), testEvent("event-2", "Hack Night")} {
+		_, err := s.UpsertEvent(event)
+		assert.NoError(t, err)
+	}
+	for _, a := range []*models.Attendee{
+		models.NewAttendee("Élan", "Ébène Élan", "user 1", nil, nil, false),
+		models.NewAttendee("ébène", "élan ébène", "user 2", nil, nil, false),
+	} {
+		_, err := s.UpsertAttendee(a)
+		assert.NoError(t, err)
+	}
+	events, err := s.QueryEvents(storage.EventQuery{NameContains: "ÉCLAIR"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-1"}, eventIDs(events))
+
+	result, err := s.UpsertEvent(testEvent("event-2", "ÉCLAIR Redux"))
+	assertUpsert(t, storage.Updated, result, err)
+	events, err = s.QueryEvents(storage.EventQuery{NameContains: "éclair r"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-2"}, eventIDs(events))
+
+	attendees, err := s.QueryAttendees(storage.AttendeeQuery{Sort: storage.SortAttendeesByName})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user 2", "user 1"}, userIDs(attendees))
+	attendees, err = s.QueryAttendees(storage.AttendeeQuery{Sort: storage.SortAttendeesByLegalName})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user 1", "user 2"}, userIDs(attendees))
+}
+
+func testTransactionCommit(t *testing.T, s storage.Store) {
+	err := s.WithTx(func(tx storage.Store) error {
+		if _, err := tx.UpsertEvent(testEvent("event-1", "Hack Night")); err != nil {
+			return err
+		}
+		_, err := tx.UpsertAttendee(testAttendee(t, "user 1", "Alex Mitchell"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	_, err = s.FetchEvent("event-1")
+	assert.NoError(t, err)
+	_, err = s.FetchAttendee("user 1")
+	assert.NoError(t, err)
+}
+
+func testTransactionRollback(t *testing.T, s storage.Store) {
+	_, err := s.UpsertEvent(testEvent("event-1", "Hack Night"))
+	assert.NoError(t, err)
+
+	err = s.WithTx(func(tx storage.Store) error {
+		if _, err := tx.UpsertEvent(testEvent("event-1", "Renamed")); err != nil {
+			return err
+		}
+		if _, err := tx.UpsertAttendee(testAttendee(t, "user 1", "Alex Mitchell")); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+
+	event, err := s.FetchEvent("event-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hack Night", event.Name())
+	_, err = s.FetchAttendee("user 1")
+	assert.Equal(t, storage.ErrNoEntryWithUserID, errors.Cause(err))
+}
@@ -0,0 +1,14 @@
+package storage
+
+// AliasStorage remembers the user IDs of attendees that were merged into
+// others, so that records still carrying an old ID are attributed to the
+// attendee that survived the merge.
+type AliasStorage interface {
+	// ResolveUserID returns the user ID that userID was merged into, or
+	// userID itself if it never was.
+	ResolveUserID(userID string) (string, error)
+	// GetAliases returns the sorted user IDs that were merged into userID.
+	GetAliases(userID string) ([]string, error)
+	// UpsertAlias records that alias now refers to userID.
+	UpsertAlias(alias, userID string) (UpsertResult, error)
+}
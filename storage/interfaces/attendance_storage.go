@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+type AttendanceStorage interface {
+	CountAttendances() (uint, error)
+	FetchAttendance(eventID, userID string) (*models.Attendance, error)
+	GetAttendancesForEvent(eventID string) ([]*models.Attendance, error)
+	GetAttendancesForAttendee(userID string) ([]*models.Attendance, error)
+	UpsertAttendance(attendance *models.Attendance) (UpsertResult, error)
+	DeleteAttendance(eventID, userID string) error
+}
@@ -6,7 +6,10 @@ import (
 
 type AttendeeStorage interface {
 	CountAttendees() (uint, error)
+	GetAllAttendees() ([]*models.Attendee, error)
+	QueryAttendees(query AttendeeQuery) ([]*models.Attendee, error)
 	FetchAttendee(userID string) (*models.Attendee, error)
-	UpsertAttendee(attendee *models.Attendee) error
+	UpsertAttendee(attendee *models.Attendee) (UpsertResult, error)
+	// DeleteAttendee deletes the attendee with their attendances.
 	DeleteAttendee(userID string) error
 }
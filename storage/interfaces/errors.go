@@ -0,0 +1,11 @@
+package storage
+
+import "github.com/pkg/errors"
+
+// Every backend wraps these errors when the requested entry doesn't exist,
+// so callers can check for them with errors.Cause regardless of backend.
+var (
+	ErrNoEntryWithUserID  = errors.New("no entry exists with the given user ID")
+	ErrNoEntryWithEventID = errors.New("no entry exists with the given event identifier")
+	ErrNoAttendanceEntry  = errors.New("no attendance entry exists for the given event and user ID")
+)
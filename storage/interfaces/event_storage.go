@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+type EventStorage interface {
+	CountEvents() (uint, error)
+	GetAllEvents() ([]*models.Event, error)
+	QueryEvents(query EventQuery) ([]*models.Event, error)
+	FetchEvent(eventID string) (*models.Event, error)
+	UpsertEvent(event *models.Event) (UpsertResult, error)
+	// DeleteEvent deletes the event with its attendances and imports.
+	DeleteEvent(eventID string) error
+}
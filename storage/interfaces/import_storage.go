@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+// ImportStorage keeps the history of files imported into each event.
+type ImportStorage interface {
+	// RecordImport adds an import to its event's history.
+	RecordImport(record *models.Import) error
+	// GetImportsForEvent returns the event's imports, oldest first.
+	GetImportsForEvent(eventID string) ([]*models.Import, error)
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Sort keys accepted by EventQuery. Prefix a key with "-" to reverse it.
+const (
+	SortEventsByTime = "time"
+	SortEventsByName = "name"
+)
+
+// Sort keys accepted by AttendeeQuery. Prefix a key with "-" to reverse it.
+const (
+	SortAttendeesByName      = "name"
+	SortAttendeesByLegalName = "legal-name"
+	SortAttendeesByJoined    = "joined"
+	SortAttendeesByUserID    = "user-id"
+)
+
+// EventSortKeys returns the sort keys EventQuery accepts, default first.
+func EventSortKeys() []string {
+	return []string{SortEventsByTime, SortEventsByName}
+}
+
+// AttendeeSortKeys returns the sort keys AttendeeQuery accepts, default
+// first.
+func AttendeeSortKeys() []string {
+	return []string{SortAttendeesByName, SortAttendeesByLegalName, SortAttendeesByJoined, SortAttendeesByUserID}
+}
+
+// EventQuery selects a page of events. The zero value selects every event,
+// oldest first.
+type EventQuery struct {
+	// From and To bound the event time. From is inclusive and To exclusive;
+	// a nil bound is open.
+	From, To *time.Time
+	// NameContains keeps only events whose name contains it, ignoring case.
+	NameContains string
+	// SourceID keeps only events with exactly this source ID, if not
+	// empty.
+	SourceID string
+	// Sort is one of EventSortKeys, optionally prefixed with "-" for
+	// descending order. Ties are broken by event ID.
+	Sort string
+	// Limit caps the number of events returned if positive. Offset skips
+	// that many events first.
+	Limit, Offset int
+}
+
+// Validate reports an unknown sort key or a negative limit or offset.
+func (q EventQuery) Validate() error {
+	if err := validatePage(q.Limit, q.Offset); err != nil {
+		return err
+	}
+	_, _, err := parseSort(q.Sort, EventSortKeys())
+	return err
+}
+
+// SortKey returns the key to sort by and whether the order is descending.
+// The query must be valid.
+func (q EventQuery) SortKey() (key string, descending bool) {
+	key, descending, _ = parseSort(q.Sort, EventSortKeys())
+	return key, descending
+}
+
+// AttendeeQuery selects a page of attendees. The zero value selects every
+// attendee, ordered by preferred name.
+type AttendeeQuery struct {
+	// IsHost keeps only hosts if it points to true, and only non-hosts if
+	// it points to false.
+	IsHost *bool
+	// JoinedFrom and JoinedTo bound the joined date. JoinedFrom is
+	// inclusive and JoinedTo exclusive; a nil bound is open. Attendees with
+	// no joined date are left out when either bound is set.
+	JoinedFrom, JoinedTo *time.Time
+	// AttendedEventID keeps only attendees who attended the event: those
+	// checked in or walked in, or, if no check-ins were recorded at the
+	// event at all, those who RSVPed yes.
+	AttendedEventID string
+	// Sort is one of AttendeeSortKeys, optionally prefixed with "-" for
+	// descending order. Ties are broken by user ID, and attendees without
+	// a joined date sort last either way.
+	Sort string
+	// Limit caps the number of attendees returned if positive. Offset skips
+	// that many attendees first.
+	Limit, Offset int
+}
+
+// Validate reports an unknown sort key or a negative limit or offset.
+func (q AttendeeQuery) Validate() error {
+	if err := validatePage(q.Limit, q.Offset); err != nil {
+		return err
+	}
+	_, _, err := parseSort(q.Sort, AttendeeSortKeys())
+	return err
+}
+
+// SortKey returns the key to sort by and whether the order is descending.
+// The query must be valid.
+func (q AttendeeQuery) SortKey() (key string, descending bool) {
+	key, descending, _ = parseSort(q.Sort, AttendeeSortKeys())
+	return key, descending
+}
+
+func validatePage(limit, offset int) error {
+	if limit < 0 {
+		return errors.Errorf("limit must not be negative, got %d", limit)
+	}
+	if offset < 0 {
+		return errors.Errorf("offset must not be negative, got %d", offset)
+	}
+	return nil
+}
+
+// parseSort splits a sort such as "-joined" into its key and direction. An
+// empty sort means the first key, ascending.
+func parseSort(sort string, keys []string) (string, bool, error) {
+	descending := strings.HasPrefix(sort, "-")
+	key := strings.TrimPrefix(sort, "-")
+	if key == "" {
+		return keys[0], descending, nil
+	}
+	for _, k := range keys {
+		if k == key {
+			return key, descending, nil
+		}
+	}
+	return "", false, errors.Errorf("unknown sort key %#v (known: %s)", key, strings.Join(keys, ", "))
+}
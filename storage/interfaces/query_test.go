@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventQuerySortKey(t *testing.T) {
+	key, descending := EventQuery{}.SortKey()
+	assert.Equal(t, SortEventsByTime, key)
+	assert.False(t, descending)
+
+	query := EventQuery{Sort: "-name"}
+	assert.NoError(t, query.Validate())
+	key, descending = query.SortKey()
+	assert.Equal(t, SortEventsByName, key)
+	assert.True(t, descending)
+
+	assert.Error(t, EventQuery{Sort: "joined"}.Validate())
+}
+
+func TestAttendeeQueryValidate(t *testing.T) {
+	assert.NoError(t, AttendeeQuery{Sort: "-joined", Limit: 10, Offset: 20}.Validate())
+	assert.Error(t, AttendeeQuery{Sort: "time"}.Validate())
+	assert.Error(t, AttendeeQuery{Limit: -1}.Validate())
+	assert.Error(t, AttendeeQuery{Offset: -1}.Validate())
+
+	key, descending := AttendeeQuery{Sort: "-"}.SortKey()
+	assert.Equal(t, SortAttendeesByName, key)
+	assert.True(t, descending)
+}
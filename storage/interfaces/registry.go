@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultScheme is used for DSNs without a scheme, which are treated as a
+// path to a local database file.
+const DefaultScheme = "sqlite"
+
+// Opener opens a Store from a DSN such as "sqlite:///path/to/file.db". The
+// DSN is passed through unchanged, scheme included.
+type Opener func(dsn string) (Store, error)
+
+var (
+	openersMu sync.RWMutex
+	openers   = map[string]Opener{}
+)
+
+// Register makes a storage backend available under the given DSN scheme.
+// It is intended to be called from the init function of the backend's
+// package, and panics if the scheme is registered twice.
+func Register(scheme string, opener Opener) {
+	openersMu.Lock()
+	defer openersMu.Unlock()
+	if opener == nil {
+		panic("storage: Register opener is nil")
+	}
+	if _, dup := openers[scheme]; dup {
+		panic("storage: Register called twice for scheme " + scheme)
+	}
+	openers[scheme] = opener
+}
+
+// Schemes returns the sorted list of registered DSN schemes.
+func Schemes() []string {
+	openersMu.RLock()
+	defer openersMu.RUnlock()
+	var schemes []string
+	for scheme := range openers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// SchemeOf returns the scheme of dsn, or DefaultScheme if it has none.
+func SchemeOf(dsn string) string {
+	if i := strings.Index(dsn, "://"); i > 0 {
+		return dsn[:i]
+	}
+	return DefaultScheme
+}
+
+// Open opens a Store with the backend registered for the DSN's scheme.
+func Open(dsn string) (Store, error) {
+	scheme := SchemeOf(dsn)
+	openersMu.RLock()
+	opener, ok := openers[scheme]
+	openersMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unknown storage scheme %#v (known: %s)", scheme, strings.Join(Schemes(), ", "))
+	}
+	store, err := opener(dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening %s storage", scheme)
+	}
+	return store, nil
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemeOf(t *testing.T) {
+	assert.Equal(t, "sqlite", SchemeOf("sqlite:///tmp/attendance.db"))
+	assert.Equal(t, "memory", SchemeOf("memory://"))
+	assert.Equal(t, "postgres", SchemeOf("postgres://localhost/attendance"))
+	assert.Equal(t, DefaultScheme, SchemeOf("attendance.db"))
+}
+
+func TestOpenUnknownScheme(t *testing.T) {
+	_, err := Open("nosuchbackend://somewhere")
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package storage
+
+// Store is everything a storage backend provides.
+type Store interface {
+	AttendeeStorage
+	EventStorage
+	AttendanceStorage
+	AliasStorage
+	ImportStorage
+
+	// WithTx runs fn against a Store whose operations are applied
+	// atomically: all of them if fn returns nil, none of them otherwise.
+	WithTx(fn func(tx Store) error) error
+	Close()
+}
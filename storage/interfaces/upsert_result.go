@@ -0,0 +1,21 @@
+package storage
+
+// UpsertResult reports what an upsert did to the underlying row.
+type UpsertResult int
+
+const (
+	Unchanged UpsertResult = iota
+	Inserted
+	Updated
+)
+
+func (r UpsertResult) String() string {
+	switch r {
+	case Inserted:
+		return "inserted"
+	case Updated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func (s *MemoryStorage) ResolveUserID(userID string) (string, error) {
+	defer s.lock()()
+	if target, ok := s.state.aliases[userID]; ok {
+		return target, nil
+	}
+	return userID, nil
+}
+
+func (s *MemoryStorage) GetAliases(userID string) ([]string, error) {
+	defer s.lock()()
+	var aliases []string
+	for alias, target := range s.state.aliases {
+		if target == userID {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases, nil
+}
+
+func (s *MemoryStorage) UpsertAlias(alias, userID string) (interfaces.UpsertResult, error) {
+	if alias == userID {
+		return interfaces.Unchanged, errors.Errorf("error upserting alias: %#v can't be an alias of itself", alias)
+	}
+	defer s.lock()()
+	existing, ok := s.state.aliases[alias]
+	s.state.aliases[alias] = userID
+	switch {
+	case !ok:
+		return interfaces.Inserted, nil
+	case existing == userID:
+		return interfaces.Unchanged, nil
+	default:
+		return interfaces.Updated, nil
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func (s *MemoryStorage) CountAttendances() (uint, error) {
+	defer s.lock()()
+	return uint(len(s.state.attendances)), nil
+}
+
+// attendanceModel joins an attendance with its attendee and event. Like the
+// SQL backend's JOIN, records whose attendee or event is missing are hidden.
+func (s *MemoryStorage) attendanceModel(key attendanceKey) (*models.Attendance, bool) {
+	record, ok := s.state.attendances[key]
+	if !ok {
+		return nil, false
+	}
+	attendee, ok := s.state.attendees[key.userID]
+	if !ok {
+		return nil, false
+	}
+	event, ok := s.state.events[key.eventID]
+	if !ok {
+		return nil, false
+	}
+	attendance := models.NewAttendance(attendee.model(), event.model(), record.rsvp, copyTime(record.rsvpTime))
+	return attendance.WithCheckIn(record.checkInStatus, copyTime(record.checkInTime), record.checkedInBy).WithCancelled(record.cancelled), true
+}
+
+func (s *MemoryStorage) filterAttendances(match func(attendanceKey) bool) []*models.Attendance {
+	var attendances []*models.Attendance
+	for _, key := range s.state.attendanceOrder {
+		if !match(key) {
+			continue
+		}
+		if attendance, ok := s.attendanceModel(key); ok {
+			attendances = append(attendances, attendance)
+		}
+	}
+	return attendances
+}
+
+func (s *MemoryStorage) GetAttendancesForEvent(eventID string) ([]*models.Attendance, error) {
+	defer s.lock()()
+	return s.filterAttendances(func(key attendanceKey) bool {
+		return key.eventID == eventID
+	}), nil
+}
+
+func (s *MemoryStorage) GetAttendancesForAttendee(userID string) ([]*models.Attendance, error) {
+	defer s.lock()()
+	return s.filterAttendances(func(key attendanceKey) bool {
+		return key.userID == userID
+	}), nil
+}
+
+func (s *MemoryStorage) FetchAttendance(eventID, userID string) (*models.Attendance, error) {
+	defer s.lock()()
+	attendance, ok := s.attendanceModel(attendanceKey{eventID: eventID, userID: userID})
+	if !ok {
+		return nil, errors.Wrapf(interfaces.ErrNoAttendanceEntry, "error fetching attendance for event %#v and user %#v", eventID, userID)
+	}
+	return attendance, nil
+}
+
+func (s *MemoryStorage) UpsertAttendance(attendance *models.Attendance) (interfaces.UpsertResult, error) {
+	defer s.lock()()
+	key := attendanceKey{eventID: attendance.Event().ID(), userID: attendance.Attendee().UserID()}
+	record := &attendanceRecord{
+		rsvp:          attendance.RSVP(),
+		rsvpTime:      normalizeTime(attendance.RSVPTime()),
+		checkInStatus: attendance.CheckInStatus(),
+		checkInTime:   normalizeTime(attendance.CheckInTime()),
+		checkedInBy:   attendance.CheckedInBy(),
+		cancelled:     attendance.Cancelled(),
+	}
+	existing, ok := s.state.attendances[key]
+	switch {
+	case !ok:
+		s.state.attendances[key] = record
+		s.state.attendanceOrder = append(s.state.attendanceOrder, key)
+		return interfaces.Inserted, nil
+	case existing.equal(record):
+		return interfaces.Unchanged, nil
+	default:
+		s.state.attendances[key] = record
+		return interfaces.Updated, nil
+	}
+}
+
+func (r *attendanceRecord) equal(o *attendanceRecord) bool {
+	return r.rsvp == o.rsvp && timesEqual(r.rsvpTime, o.rsvpTime) &&
+		r.checkInStatus == o.checkInStatus && timesEqual(r.checkInTime, o.checkInTime) &&
+		r.checkedInBy == o.checkedInBy && r.cancelled == o.cancelled
+}
+
+func (s *MemoryStorage) DeleteAttendance(eventID, userID string) error {
+	defer s.lock()()
+	key := attendanceKey{eventID: eventID, userID: userID}
+	s.state.deleteAttendances(func(k attendanceKey) bool { return k == key })
+	return nil
+}
+
+// deleteAttendances deletes the attendances whose keys match.
+func (m *memoryState) deleteAttendances(match func(key attendanceKey) bool) {
+	var order []attendanceKey
+	for _, key := range m.attendanceOrder {
+		if match(key) {
+			delete(m.attendances, key)
+		} else {
+			order = append(order, key)
+		}
+	}
+	m.attendanceOrder = order
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+type attendeeRow struct {
+	preferredName string
+	legalName     string
+	userID        string
+	profileURL    *url.URL
+	isHost        bool
+	joinedDate    *time.Time
+	email         string
+}
+
+func newAttendeeRow(attendee *models.Attendee) *attendeeRow {
+	return &attendeeRow{
+		preferredName: attendee.PreferredName(),
+		legalName:     attendee.LegalName(),
+		userID:        attendee.UserID(),
+		profileURL:    normalizeURL(attendee.ProfileURL()),
+		isHost:        attendee.IsHost(),
+		joinedDate:    normalizeTime(attendee.JoinedDate()),
+		email:         attendee.Email(),
+	}
+}
+
+func (r *attendeeRow) equal(o *attendeeRow) bool {
+	return r.preferredName == o.preferredName &&
+		r.legalName == o.legalName &&
+		r.profileURL.String() == o.profileURL.String() &&
+		r.isHost == o.isHost &&
+		timesEqual(r.joinedDate, o.joinedDate) &&
+		r.email == o.email
+}
+
+// model returns a fresh copy so callers can never mutate stored rows.
+func (r *attendeeRow) model() *models.Attendee {
+	u := *r.profileURL
+	return models.NewAttendee(r.preferredName, r.legalName, r.userID, &u, copyTime(r.joinedDate), r.isHost).WithEmail(r.email)
+}
+
+func (s *MemoryStorage) lock() func() {
+	if s.inTx {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (s *MemoryStorage) CountAttendees() (uint, error) {
+	defer s.lock()()
+	return uint(len(s.state.attendees)), nil
+}
+
+func (s *MemoryStorage) GetAllAttendees() ([]*models.Attendee, error) {
+	defer s.lock()()
+	var attendees []*models.Attendee
+	for _, userID := range s.state.attendeeOrder {
+		if row, ok := s.state.attendees[userID]; ok {
+			attendees = append(attendees, row.model())
+		}
+	}
+	return attendees, nil
+}
+
+func (s *MemoryStorage) QueryAttendees(query interfaces.AttendeeQuery) ([]*models.Attendee, error) {
+	if err := query.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error querying attendees")
+	}
+	defer s.lock()()
+	joinedFrom, joinedTo := normalizeTime(query.JoinedFrom), normalizeTime(query.JoinedTo)
+	checkInsTaken := query.AttendedEventID != "" && s.state.checkInsTaken(query.AttendedEventID)
+	var rows []*attendeeRow
+	for _, userID := range s.state.attendeeOrder {
+		row, ok := s.state.attendees[userID]
+		if !ok {
+			continue
+		}
+		if query.IsHost != nil && row.isHost != *query.IsHost {
+			continue
+		}
+		if (joinedFrom != nil || joinedTo != nil) && !inRange(row.joinedDate, joinedFrom, joinedTo) {
+			continue
+		}
+		if query.AttendedEventID != "" {
+			record, ok := s.state.attendances[attendanceKey{eventID: query.AttendedEventID, userID: userID}]
+			if !ok || !record.attended(checkInsTaken) {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	key, descending := query.SortKey()
+	sort.SliceStable(rows, func(i, j int) bool {
+		return attendeeLess(rows[i], rows[j], key, descending)
+	})
+	start, end := page(len(rows), query.Limit, query.Offset)
+	var attendees []*models.Attendee
+	for _, row := range rows[start:end] {
+		attendees = append(attendees, row.model())
+	}
+	return attendees, nil
+}
+
+// attendeeLess orders attendees the way the SQL backend's ORDER BY does.
+func attendeeLess(a, b *attendeeRow, key string, descending bool) bool {
+	var c int
+	switch key {
+	case interfaces.SortAttendeesByName:
+		c = strings.Compare(strings.ToLower(a.preferredName), strings.ToLower(b.preferredName))
+	case interfaces.SortAttendeesByLegalName:
+		c = strings.Compare(strings.ToLower(a.legalName), strings.ToLower(b.legalName))
+	case interfaces.SortAttendeesByJoined:
+		if (a.joinedDate == nil) != (b.joinedDate == nil) {
+			return a.joinedDate != nil
+		}
+		if a.joinedDate != nil {
+			c = compareTimes(*a.joinedDate, *b.joinedDate)
+		}
+	}
+	if c == 0 {
+		c = strings.Compare(a.userID, b.userID)
+	}
+	if descending {
+		c = -c
+	}
+	return c < 0
+}
+
+func (s *MemoryStorage) FetchAttendee(userID string) (*models.Attendee, error) {
+	defer s.lock()()
+	row, ok := s.state.attendees[userID]
+	if !ok {
+		return nil, errors.Wrapf(interfaces.ErrNoEntryWithUserID, "error fetching attendee with ID %#v", userID)
+	}
+	return row.model(), nil
+}
+
+func (s *MemoryStorage) UpsertAttendee(attendee *models.Attendee) (interfaces.UpsertResult, error) {
+	defer s.lock()()
+	row := newAttendeeRow(attendee)
+	existing, ok := s.state.attendees[row.userID]
+	switch {
+	case !ok:
+		s.state.attendees[row.userID] = row
+		s.state.attendeeOrder = append(s.state.attendeeOrder, row.userID)
+		return interfaces.Inserted, nil
+	case existing.equal(row):
+		return interfaces.Unchanged, nil
+	default:
+		s.state.attendees[row.userID] = row
+		return interfaces.Updated, nil
+	}
+}
+
+func (s *MemoryStorage) DeleteAttendee(userID string) error {
+	defer s.lock()()
+	if _, ok := s.state.attendees[userID]; !ok {
+		return nil
+	}
+	delete(s.state.attendees, userID)
+	s.state.attendeeOrder = removeString(s.state.attendeeOrder, userID)
+	s.state.deleteAttendances(func(key attendanceKey) bool { return key.userID == userID })
+	return nil
+}
+
+func removeString(list []string, value string) []string {
+	var out []string
+	for _, v := range list {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+type eventRow struct {
+	name    string
+	id      string
+	time    *time.Time
+	details models.EventDetails
+}
+
+func newEventRow(event *models.Event) *eventRow {
+	details := event.Details()
+	// The SQL backend stores durations in whole seconds.
+	details.Duration = details.Duration.Truncate(time.Second)
+	return &eventRow{
+		name:    event.Name(),
+		id:      event.ID(),
+		time:    normalizeTime(event.Time()),
+		details: details,
+	}
+}
+
+func (r *eventRow) equal(o *eventRow) bool {
+	return r.name == o.name && timesEqual(r.time, o.time) && r.details == o.details
+}
+
+func (r *eventRow) model() *models.Event {
+	return models.NewEvent(r.name, r.id, copyTime(r.time)).WithDetails(r.details)
+}
+
+func (s *MemoryStorage) CountEvents() (uint, error) {
+	defer s.lock()()
+	return uint(len(s.state.events)), nil
+}
+
+func (s *MemoryStorage) GetAllEvents() ([]*models.Event, error) {
+	defer s.lock()()
+	var events []*models.Event
+	for _, id := range s.state.eventOrder {
+		if row, ok := s.state.events[id]; ok {
+			events = append(events, row.model())
+		}
+	}
+	return events, nil
+}
+
+func (s *MemoryStorage) QueryEvents(query interfaces.EventQuery) ([]*models.Event, error) {
+	if err := query.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error querying events")
+	}
+	defer s.lock()()
+	from, to := normalizeTime(query.From), normalizeTime(query.To)
+	nameContains := strings.ToLower(query.NameContains)
+	var rows []*eventRow
+	for _, id := range s.state.eventOrder {
+		row, ok := s.state.events[id]
+		if !ok || !inRange(row.time, from, to) {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(row.name), nameContains) {
+			continue
+		}
+		if query.SourceID != "" && row.details.SourceID != query.SourceID {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	key, descending := query.SortKey()
+	sort.SliceStable(rows, func(i, j int) bool {
+		var c int
+		switch key {
+		case interfaces.SortEventsByTime:
+			c = compareTimes(*rows[i].time, *rows[j].time)
+		case interfaces.SortEventsByName:
+			c = strings.Compare(strings.ToLower(rows[i].name), strings.ToLower(rows[j].name))
+		}
+		if c == 0 {
+			c = strings.Compare(rows[i].id, rows[j].id)
+		}
+		if descending {
+			c = -c
+		}
+		return c < 0
+	})
+	start, end := page(len(rows), query.Limit, query.Offset)
+	var events []*models.Event
+	for _, row := range rows[start:end] {
+		events = append(events, row.model())
+	}
+	return events, nil
+}
+
+func (s *MemoryStorage) FetchEvent(eventID string) (*models.Event, error) {
+	defer s.lock()()
+	row, ok := s.state.events[eventID]
+	if !ok {
+		return nil, errors.Wrapf(interfaces.ErrNoEntryWithEventID, "error fetching event with ID %#v", eventID)
+	}
+	return row.model(), nil
+}
+
+func (s *MemoryStorage) UpsertEvent(event *models.Event) (interfaces.UpsertResult, error) {
+	if event.Time() == nil {
+		return interfaces.Unchanged, errors.New("error upserting event: event has no time")
+	}
+	defer s.lock()()
+	row := newEventRow(event)
+	existing, ok := s.state.events[row.id]
+	switch {
+	case !ok:
+		s.state.events[row.id] = row
+		s.state.eventOrder = append(s.state.eventOrder, row.id)
+		return interfaces.Inserted, nil
+	case existing.equal(row):
+		return interfaces.Unchanged, nil
+	default:
+		s.state.events[row.id] = row
+		return interfaces.Updated, nil
+	}
+}
+
+func (s *MemoryStorage) DeleteEvent(eventID string) error {
+	defer s.lock()()
+	if _, ok := s.state.events[eventID]; !ok {
+		return nil
+	}
+	delete(s.state.events, eventID)
+	s.state.eventOrder = removeString(s.state.eventOrder, eventID)
+	s.state.deleteAttendances(func(key attendanceKey) bool { return key.eventID == eventID })
+	var imports []*models.Import
+	for _, record := range s.state.imports {
+		if record.EventID() != eventID {
+			imports = append(imports, record)
+		}
+	}
+	s.state.imports = imports
+	return nil
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func (s *MemoryStorage) RecordImport(record *models.Import) error {
+	defer s.lock()()
+	importedAt := record.ImportedAt()
+	importedAt = *normalizeTime(&importedAt)
+	s.state.imports = append(s.state.imports, models.NewImport(record.EventID(), record.FileName(), record.SHA256(), importedAt, record.Records()))
+	return nil
+}
+
+func (s *MemoryStorage) GetImportsForEvent(eventID string) ([]*models.Import, error) {
+	defer s.lock()()
+	var imports []*models.Import
+	for _, record := range s.state.imports {
+		if record.EventID() == eventID {
+			imports = append(imports, record)
+		}
+	}
+	sort.SliceStable(imports, func(i, j int) bool {
+		return imports[i].ImportedAt().Before(imports[j].ImportedAt())
+	})
+	return imports, nil
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func init() {
+	interfaces.Register("memory", func(dsn string) (interfaces.Store, error) {
+		return NewMemoryStorage(), nil
+	})
+}
+
+type attendanceKey struct {
+	eventID string
+	userID  string
+}
+
+type attendanceRecord struct {
+	rsvp          bool
+	rsvpTime      *time.Time
+	checkInStatus models.CheckInStatus
+	checkInTime   *time.Time
+	checkedInBy   string
+	cancelled     bool
+}
+
+// attended matches the SQL backend's attendedCondition. checkInsTaken
+// says whether anyone's check-in was recorded at the event.
+func (r *attendanceRecord) attended(checkInsTaken bool) bool {
+	if r.checkInStatus != models.NotCheckedIn {
+		return r.checkInStatus == models.CheckedIn || r.checkInStatus == models.WalkIn
+	}
+	return r.rsvp && !checkInsTaken
+}
+
+// checkInsTaken reports whether anyone's check-in was recorded at the
+// event.
+func (m *memoryState) checkInsTaken(eventID string) bool {
+	for key, record := range m.attendances {
+		if key.eventID == eventID && record.checkInStatus != models.NotCheckedIn {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryState holds the rows of every table. Each table keeps the order in
+// which keys were first inserted so listings are stable, like SQLite's rowid
+// order.
+type memoryState struct {
+	attendees       map[string]*attendeeRow
+	attendeeOrder   []string
+	events          map[string]*eventRow
+	eventOrder      []string
+	attendances     map[attendanceKey]*attendanceRecord
+	attendanceOrder []attendanceKey
+	// aliases maps the user ID of a merged attendee to the one it was
+	// merged into.
+	aliases map[string]string
+	imports []*models.Import
+}
+
+func newMemoryState() *memoryState {
+	return &memoryState{
+		attendees:   map[string]*attendeeRow{},
+		events:      map[string]*eventRow{},
+		attendances: map[attendanceKey]*attendanceRecord{},
+		aliases:     map[string]string{},
+	}
+}
+
+// clone copies the state deeply enough that changes to the copy never
+// affect the original. Rows are immutable once stored, so they are shared.
+func (m *memoryState) clone() *memoryState {
+	c := newMemoryState()
+	for k, v := range m.attendees {
+		c.attendees[k] = v
+	}
+	for k, v := range m.events {
+		c.events[k] = v
+	}
+	for k, v := range m.attendances {
+		c.attendances[k] = v
+	}
+	for k, v := range m.aliases {
+		c.aliases[k] = v
+	}
+	c.attendeeOrder = append([]string(nil), m.attendeeOrder...)
+	c.eventOrder = append([]string(nil), m.eventOrder...)
+	c.attendanceOrder = append([]attendanceKey(nil), m.attendanceOrder...)
+	c.imports = append([]*models.Import(nil), m.imports...)
+	return c
+}
+
+// MemoryStorage is a thread-safe, non-persistent implementation of
+// interfaces.Store with the same semantics as the SQL backend. It is meant
+// for tests and dry runs.
+type MemoryStorage struct {
+	mu    sync.Mutex
+	state *memoryState
+	inTx  bool
+}
+
+var _ interfaces.Store = &MemoryStorage{}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{state: newMemoryState()}
+}
+
+func (s *MemoryStorage) Close() {}
+
+// WithTx runs fn against a copy of the data and only keeps the changes if fn
+// returns nil. Other callers are blocked until the transaction finishes.
+func (s *MemoryStorage) WithTx(fn func(tx interfaces.Store) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := &MemoryStorage{state: s.state.clone(), inTx: true}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.state = tx.state
+	return nil
+}
+
+// sqlTimestampFormat matches the precision the SQL backend stores, so both
+// backends return the same values and agree on whether a row changed. Like
+// the SQL backend, times are kept in UTC.
+const sqlTimestampFormat = "2006-01-02T15:04:05Z"
+
+func normalizeTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	normalized, err := time.Parse(sqlTimestampFormat, t.UTC().Format(sqlTimestampFormat))
+	if err != nil {
+		panic(errors.Wrap(err, "error normalizing timestamp"))
+	}
+	return &normalized
+}
+
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func normalizeURL(u *url.URL) *url.URL {
+	var s string
+	if u != nil {
+		s = u.String()
+	}
+	normalized, err := url.Parse(s)
+	if err != nil {
+		panic(errors.Wrap(err, "error normalizing URL"))
+	}
+	return normalized
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// inRange reports whether t falls within [from, to). Nil bounds are open,
+// but a nil t is only in range when both are.
+func inRange(t, from, to *time.Time) bool {
+	if t == nil {
+		return from == nil && to == nil
+	}
+	return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+// page returns the bounds of the slice of n sorted rows selected by limit
+// and offset.
+func page(n, limit, offset int) (start, end int) {
+	start = offset
+	if start > n {
+		start = n
+	}
+	end = n
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	return start, end
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	"github.com/alexthemitchell/community-attendance/storage/conformance"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (interfaces.Store, func()) {
+		return NewMemoryStorage(), func() {}
+	})
+}
+
+func TestOpenFromRegistry(t *testing.T) {
+	store, err := interfaces.Open("memory://")
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryStorage{}, store)
+}
+
+func TestConcurrentUpserts(t *testing.T) {
+	store := NewMemoryStorage()
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.UpsertEvent(models.NewEvent("Event", string(rune('a'+i%26)), &now))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	count, err := store.CountEvents()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(26), count)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+const (
+	createAttendeeAliasesTableStatement = "CREATE TABLE attendee_aliases (alias varchar(255) primary key not null, user_id varchar(255) not null)"
+	selectAliasTargetQuery              = "SELECT user_id FROM attendee_aliases WHERE alias=?"
+	selectAliasesQuery                  = "SELECT alias FROM attendee_aliases WHERE user_id=? ORDER BY alias"
+	aliasExistsQuery                    = "SELECT COUNT(*) FROM attendee_aliases WHERE alias=?"
+	upsertAliasStatement                = "INSERT INTO attendee_aliases(alias, user_id) VALUES (?,?) ON CONFLICT(alias) DO UPDATE SET user_id=excluded.user_id WHERE attendee_aliases.user_id %[1]s excluded.user_id"
+)
+
+func (s *SQLStorage) ResolveUserID(userID string) (string, error) {
+	var target string
+	err := s.q.QueryRow(selectAliasTargetQuery, userID).Scan(&target)
+	switch {
+	case err == sql.ErrNoRows:
+		return userID, nil
+	case err != nil:
+		return "", errors.Wrapf(err, "error resolving user ID %#v", userID)
+	}
+	return target, nil
+}
+
+func (s *SQLStorage) GetAliases(userID string) ([]string, error) {
+	rows, err := s.q.Query(selectAliasesQuery, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error querying aliases of %#v", userID)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, errors.Wrap(err, "error scanning alias")
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, errors.Wrap(rows.Err(), "error reading aliases")
+}
+
+func (s *SQLStorage) UpsertAlias(alias, userID string) (interfaces.UpsertResult, error) {
+	if alias == userID {
+		return interfaces.Unchanged, errors.Errorf("error upserting alias: %#v can't be an alias of itself", alias)
+	}
+	result, err := s.upsert(aliasExistsQuery, []interface{}{alias}, upsertAliasStatement, alias, userID)
+	if err != nil {
+		return result, errors.Wrap(err, "error upserting alias")
+	}
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestUpsertAndResolveAlias(t *testing.T) {
+	storage, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	resolved, err := storage.ResolveUserID("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user 1", resolved)
+
+	result, err := storage.UpsertAlias("user 1", "user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Inserted, result)
+	result, err = storage.UpsertAlias("user 1", "user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Unchanged, result)
+	result, err = storage.UpsertAlias("user 1", "user 3")
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Updated, result)
+
+	resolved, err = storage.ResolveUserID("user 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user 3", resolved)
+	aliases, err := storage.GetAliases("user 3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user 1"}, aliases)
+
+	_, err = storage.UpsertAlias("user 3", "user 3")
+	assert.Error(t, err)
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"database/sql"
+	"net/url"
+	gotime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+const (
+	countAttendancesQuery                 = "SELECT COUNT(*) FROM attendances"
+	createAttendancesTableStatement       = "CREATE TABLE IF NOT EXISTS attendances (event_id varchar(36) not null, user_id varchar(255) not null, rsvp boolean, rsvp_time DATETIME, PRIMARY KEY(event_id, user_id))"
+	insertAttendanceStatement             = "INSERT INTO attendances(event_id, user_id, rsvp, rsvp_time, check_in_status, check_in_time, checked_in_by, cancelled) VALUES (?,?,?,?,?,?,?,?)"
+	deleteAttendanceStatement             = "DELETE FROM attendances WHERE event_id=? AND user_id=?"
+	updateAttendanceStatement             = "UPDATE attendances SET rsvp=?, rsvp_time=?, check_in_status=?, check_in_time=?, checked_in_by=?, cancelled=? WHERE event_id=? AND user_id=?"
+	attendanceExistsQuery                 = "SELECT COUNT(*) FROM attendances WHERE event_id=? AND user_id=?"
+	addCheckInStatusColumnStatement       = "ALTER TABLE attendances ADD COLUMN check_in_status varchar(16) not null default ''"
+	addCheckInTimeColumnStatement         = "ALTER TABLE attendances ADD COLUMN check_in_time DATETIME"
+	addCheckedInByColumnStatement         = "ALTER TABLE attendances ADD COLUMN checked_in_by varchar(255) not null default ''"
+	addCancelledColumnStatement           = "ALTER TABLE attendances ADD COLUMN cancelled boolean not null default FALSE"
+	upsertAttendanceStatement             = insertAttendanceStatement + " ON CONFLICT(event_id, user_id) DO UPDATE SET rsvp=excluded.rsvp, rsvp_time=excluded.rsvp_time, check_in_status=excluded.check_in_status, check_in_time=excluded.check_in_time, checked_in_by=excluded.checked_in_by, cancelled=excluded.cancelled WHERE attendances.rsvp %[1]s excluded.rsvp OR attendances.rsvp_time %[1]s excluded.rsvp_time OR attendances.check_in_status %[1]s excluded.check_in_status OR attendances.check_in_time %[1]s excluded.check_in_time OR attendances.checked_in_by %[1]s excluded.checked_in_by OR attendances.cancelled %[1]s excluded.cancelled"
+	selectAttendanceColumns               = "SELECT a.rsvp, a.rsvp_time, a.check_in_status, a.check_in_time, a.checked_in_by, a.cancelled, e.name, e.id, e.time, e.venue_name, e.venue_address, e.capacity, e.group_name, e.series_id, e.duration_seconds, e.time_zone, e.source_id, u.preferred_name, u.legal_name, u.user_id, u.profile_url, u.is_host, u.joined_date, u.email FROM attendances a JOIN events e ON e.id = a.event_id JOIN attendees u ON u.user_id = a.user_id"
+	selectAttendanceStatement             = selectAttendanceColumns + " WHERE a.event_id=? AND a.user_id=?"
+	selectAttendancesForEventStatement    = selectAttendanceColumns + " WHERE a.event_id=?"
+	selectAttendancesForAttendeeStatement = selectAttendanceColumns + " WHERE a.user_id=?"
+)
+
+var (
+	ErrNoAttendanceEntry = interfaces.ErrNoAttendanceEntry
+)
+
+func (s *SQLStorage) CountAttendances() (uint, error) {
+	stmt, err := s.q.Prepare(countAttendancesQuery)
+	if err != nil {
+		return 0, errors.Wrap(err, "error preparing attendances count query")
+	}
+	result, err := stmt.Query()
+	if err != nil {
+		return 0, errors.Wrap(err, "error querying for attendances count")
+	}
+	defer result.Close()
+	if !result.Next() {
+		return 0, errors.New("unexpected SQL result")
+	}
+	var count uint
+	result.Scan(&count)
+
+	return count, nil
+}
+
+func (s *SQLStorage) UpsertAttendance(attendance *models.Attendance) (interfaces.UpsertResult, error) {
+	eventID, userID := attendance.Event().ID(), attendance.Attendee().UserID()
+	result, err := s.upsert(attendanceExistsQuery, []interface{}{eventID, userID}, upsertAttendanceStatement,
+		attendanceColumnValues(attendance)...)
+	if err != nil {
+		return result, errors.Wrap(err, "error upserting attendance")
+	}
+	return result, nil
+}
+
+// attendanceColumnValues lists an attendance's values in the column order
+// of insertAttendanceStatement.
+func attendanceColumnValues(attendance *models.Attendance) []interface{} {
+	return []interface{}{
+		attendance.Event().ID(),
+		attendance.Attendee().UserID(),
+		attendance.RSVP(),
+		sqlTimestampOrNull(attendance.RSVPTime()),
+		string(attendance.CheckInStatus()),
+		sqlTimestampOrNull(attendance.CheckInTime()),
+		attendance.CheckedInBy(),
+		attendance.Cancelled(),
+	}
+}
+
+// sqlTimestampOrNull formats t in UTC, the zone every timestamp is stored
+// in.
+func sqlTimestampOrNull(t *gotime.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(sqlTimestampFormat)
+}
+
+func scanAttendanceFromRow(rows *sql.Rows) (*models.Attendance, error) {
+	var rsvp, cancelled bool
+	var rsvpTime, checkInTime, joinedDate nullTimestamp
+	var checkInStatus, checkedInBy string
+	var event eventFields
+	var preferredName, legalName, userID, profileURL, email string
+	var isHost bool
+	dest := []interface{}{&rsvp, &rsvpTime, &checkInStatus, &checkInTime, &checkedInBy, &cancelled}
+	dest = append(dest, event.dest()...)
+	dest = append(dest, &preferredName, &legalName, &userID, &profileURL, &isHost, &joinedDate, &email)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	parsedProfileURL, err := url.Parse(profileURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing profile URL %#v", profileURL)
+	}
+
+	attendee := models.NewAttendee(preferredName, legalName, userID, parsedProfileURL, joinedDate.Ptr(), isHost).WithEmail(email)
+	attendance := models.NewAttendance(attendee, event.model(), rsvp, rsvpTime.Ptr())
+	return attendance.WithCheckIn(models.CheckInStatus(checkInStatus), checkInTime.Ptr(), checkedInBy).WithCancelled(cancelled), nil
+}
+
+func (s *SQLStorage) queryAttendances(query string, args ...interface{}) ([]*models.Attendance, error) {
+	stmt, err := s.q.Prepare(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "error preparing attendances query")
+	}
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying for attendances")
+	}
+	defer rows.Close()
+
+	var attendances []*models.Attendance
+	for rows.Next() {
+		attendance, err := scanAttendanceFromRow(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning attendance from row")
+		}
+		attendances = append(attendances, attendance)
+	}
+	return attendances, nil
+}
+
+func (s *SQLStorage) GetAttendancesForEvent(eventID string) ([]*models.Attendance, error) {
+	return s.queryAttendances(selectAttendancesForEventStatement, eventID)
+}
+
+func (s *SQLStorage) GetAttendancesForAttendee(userID string) ([]*models.Attendance, error) {
+	return s.queryAttendances(selectAttendancesForAttendeeStatement, userID)
+}
+
+func (s *SQLStorage) FetchAttendance(eventID, userID string) (*models.Attendance, error) {
+	attendances, err := s.queryAttendances(selectAttendanceStatement, eventID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while executing select statement")
+	}
+	if len(attendances) == 0 {
+		return nil, errors.Wrapf(ErrNoAttendanceEntry, "error fetching attendance for event %#v and user %#v", eventID, userID)
+	}
+	return attendances[0], nil
+}
+
+func (s *SQLStorage) CreateAttendance(attendance *models.Attendance) error {
+	stmt, err := s.q.Prepare(insertAttendanceStatement)
+	if err != nil {
+		return errors.Wrap(err, "error while preparing insert statement")
+	}
+	_, err = stmt.Exec(attendanceColumnValues(attendance)...)
+	if err != nil {
+		return errors.Wrap(err, "error while executing insert statement")
+	}
+	return nil
+}
+
+func (s *SQLStorage) UpdateAttendance(attendance *models.Attendance) error {
+	stmt, err := s.q.Prepare(updateAttendanceStatement)
+	if err != nil {
+		return errors.Wrap(err, "error while preparing update statement")
+	}
+	_, err = stmt.Exec(attendance.RSVP(), sqlTimestampOrNull(attendance.RSVPTime()),
+		string(attendance.CheckInStatus()), sqlTimestampOrNull(attendance.CheckInTime()), attendance.CheckedInBy(), attendance.Cancelled(),
+		attendance.Event().ID(), attendance.Attendee().UserID())
+	if err != nil {
+		return errors.Wrap(err, "error while executing update statement")
+	}
+	return nil
+}
+
+func (s *SQLStorage) DeleteAttendance(eventID, userID string) error {
+	stmt, err := s.q.Prepare(deleteAttendanceStatement)
+	if err != nil {
+		return errors.Wrap(err, "error while preparing delete statement")
+	}
+	_, err = stmt.Exec(eventID, userID)
+	if err != nil {
+		return errors.Wrap(err, "error while executing delete statement")
+	}
+	return nil
+}
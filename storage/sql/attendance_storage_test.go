@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestUpsertAndFetchAttendance(t *testing.T) {
+	storage, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	eventTime := time.Date(2019, time.March, 14, 18, 30, 0, 0, time.UTC)
+	joined := time.Date(2017, time.July, 8, 0, 0, 0, 0, time.UTC)
+	rsvpTime := time.Date(2019, time.February, 18, 17, 56, 0, 0, time.UTC)
+	profile, _ := url.Parse("https://www.meetup.com/members/1/")
+	event := models.NewEvent("Hack Night", "event-1", &eventTime)
+	attendee := models.NewAttendee("Alex", "Alex Mitchell", "user 1", profile, &joined, true)
+
+	_, err := storage.UpsertEvent(event)
+	assert.NoError(t, err)
+	_, err = storage.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	result, err := storage.UpsertAttendance(models.NewAttendance(attendee, event, true, &rsvpTime))
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Inserted, result)
+	result, err = storage.UpsertAttendance(models.NewAttendance(attendee, event, true, &rsvpTime))
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Unchanged, result)
+
+	fetched, err := storage.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.True(t, fetched.RSVP())
+	assert.Equal(t, rsvpTime, *fetched.RSVPTime())
+	assert.Equal(t, "Hack Night", fetched.Event().Name())
+	assert.Equal(t, "Alex Mitchell", fetched.Attendee().LegalName())
+
+	result, err = storage.UpsertAttendance(models.NewAttendance(attendee, event, false, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Updated, result)
+	fetched, err = storage.FetchAttendance("event-1", "user 1")
+	assert.NoError(t, err)
+	assert.False(t, fetched.RSVP())
+	assert.Nil(t, fetched.RSVPTime())
+
+	byEvent, err := storage.GetAttendancesForEvent("event-1")
+	assert.NoError(t, err)
+	assert.Len(t, byEvent, 1)
+	byAttendee, err := storage.GetAttendancesForAttendee("user 1")
+	assert.NoError(t, err)
+	assert.Len(t, byAttendee, 1)
+
+	assert.NoError(t, storage.DeleteAttendance("event-1", "user 1"))
+	_, err = storage.FetchAttendance("event-1", "user 1")
+	assert.Equal(t, ErrNoAttendanceEntry, errors.Cause(err))
+}
@@ -3,33 +3,38 @@ package storage
 import (
 	"database/sql"
 	"net/url"
-	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
 )
 
 const (
 	sqlTimestampFormat = "2006-01-02T15:04:05Z"
 
-	countAttendeesQuery           = "SELECT COUNT(*) FROM attendees"
-	createAttendeesTableStatement = "CREATE TABLE IF NOT EXISTS attendees (preferred_name varchar(255), legal_name varchar(255), user_id varchar(255), profile_url varchar(1000), is_host boolean, joined_date DATETIME, UNIQUE(user_id))"
-	insertAttendeeStatement       = "INSERT INTO attendees(preferred_name, legal_name, user_id, profile_url, is_host, joined_date) VALUES (?,?,?,?,?,?)"
-	deleteAttendeeStatement       = "DELETE FROM attendees WHERE user_id=?"
-	selectAttendeeStatement       = "SELECT * FROM attendees WHERE user_id=?"
-	selectAllAttendeesStatement   = "SELECT * FROM attendees"
-	updateAttendeeStatement       = "UPDATE attendees SET preferred_name=?, legal_name=?, profile_url=?, is_host=?, joined_date=? WHERE user_id=?"
+	countAttendeesQuery             = "SELECT COUNT(*) FROM attendees"
+	createAttendeesTableStatement   = "CREATE TABLE IF NOT EXISTS attendees (preferred_name varchar(255), legal_name varchar(255), user_id varchar(255), profile_url varchar(1000), is_host boolean, joined_date DATETIME, UNIQUE(user_id))"
+	insertAttendeeStatement         = "INSERT INTO attendees(preferred_name, legal_name, user_id, profile_url, is_host, joined_date, email, preferred_name_key, legal_name_key) VALUES (?,?,?,?,?,?,?,?,?)"
+	deleteAttendeeStatement         = "DELETE FROM attendees WHERE user_id=?"
+	deleteAttendeeRowsStatement     = "DELETE FROM attendances WHERE user_id=?"
+	selectAttendeeStatement         = "SELECT preferred_name, legal_name, user_id, profile_url, is_host, joined_date, email FROM attendees WHERE user_id=?"
+	selectAllAttendeesStatement     = "SELECT preferred_name, legal_name, user_id, profile_url, is_host, joined_date, email FROM attendees"
+	updateAttendeeStatement         = "UPDATE attendees SET preferred_name=?, legal_name=?, profile_url=?, is_host=?, joined_date=?, email=?, preferred_name_key=?, legal_name_key=? WHERE user_id=?"
+	addAttendeeEmailColumnStatement = "ALTER TABLE attendees ADD COLUMN email varchar(255) not null default ''"
+	attendeeExistsQuery             = "SELECT COUNT(*) FROM attendees WHERE user_id=?"
+	upsertAttendeeStatement         = insertAttendeeStatement + " ON CONFLICT(user_id) DO UPDATE SET preferred_name=excluded.preferred_name, legal_name=excluded.legal_name, profile_url=excluded.profile_url, is_host=excluded.is_host, joined_date=excluded.joined_date, email=excluded.email, preferred_name_key=excluded.preferred_name_key, legal_name_key=excluded.legal_name_key" +
+		" WHERE attendees.preferred_name %[1]s excluded.preferred_name OR attendees.legal_name %[1]s excluded.legal_name OR attendees.profile_url %[1]s excluded.profile_url OR attendees.is_host %[1]s excluded.is_host OR attendees.joined_date %[1]s excluded.joined_date OR attendees.email %[1]s excluded.email"
 )
 
 var (
 	log                  = logrus.StandardLogger()
-	ErrNoEntryWithUserID = errors.New("no entry exists with the given user ID")
+	ErrNoEntryWithUserID = interfaces.ErrNoEntryWithUserID
 )
 
 func (s *SQLStorage) GetAllAttendees() ([]*models.Attendee, error) {
-	stmt, err := s.db.Prepare(selectAllAttendeesStatement)
+	stmt, err := s.q.Prepare(selectAllAttendeesStatement)
 	if err != nil {
 		return nil, errors.Wrap(err, "error preparing attendees count query")
 	}
@@ -37,6 +42,8 @@ func (s *SQLStorage) GetAllAttendees() ([]*models.Attendee, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "error querying for attendees count")
 	}
+	defer rows.Close()
+
 	var attendees []*models.Attendee
 	for rows.Next() {
 		attendee, err := scanAttendeeFromRow(rows)
@@ -49,8 +56,52 @@ func (s *SQLStorage) GetAllAttendees() ([]*models.Attendee, error) {
 	return attendees, nil
 }
 
+func (s *SQLStorage) QueryAttendees(options interfaces.AttendeeQuery) ([]*models.Attendee, error) {
+	if err := options.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error querying attendees")
+	}
+	q := &query{base: selectAllAttendeesStatement}
+	if options.IsHost != nil {
+		q.where("is_host = ?", *options.IsHost)
+	}
+	q.timeRange("joined_date", options.JoinedFrom, options.JoinedTo)
+	if options.AttendedEventID != "" {
+		q.where("EXISTS (SELECT 1 FROM attendances a WHERE a.user_id = attendees.user_id AND a.event_id = ? AND "+attendedCondition+")",
+			options.AttendedEventID, string(models.CheckedIn), string(models.WalkIn))
+	}
+	key, descending := options.SortKey()
+	switch key {
+	case interfaces.SortAttendeesByName:
+		q.order(descending, "preferred_name_key")
+	case interfaces.SortAttendeesByLegalName:
+		q.order(descending, "legal_name_key")
+	case interfaces.SortAttendeesByJoined:
+		// Attendees without a joined date go last in either direction.
+		q.order(false, "joined_date IS NULL")
+		q.order(descending, "joined_date")
+	}
+	q.order(descending, "user_id")
+	q.page(s.dialect, options.Limit, options.Offset)
+
+	rows, err := s.q.Query(q.String(), q.args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying attendees")
+	}
+	defer rows.Close()
+
+	var attendees []*models.Attendee
+	for rows.Next() {
+		attendee, err := scanAttendeeFromRow(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning attendee from row")
+		}
+		attendees = append(attendees, attendee)
+	}
+	return attendees, errors.Wrap(rows.Err(), "error reading attendees")
+}
+
 func (s *SQLStorage) CountAttendees() (uint, error) {
-	stmt, err := s.db.Prepare(countAttendeesQuery)
+	stmt, err := s.q.Prepare(countAttendeesQuery)
 	if err != nil {
 		return 0, errors.Wrap(err, "error preparing attendees count query")
 	}
@@ -58,6 +109,7 @@ func (s *SQLStorage) CountAttendees() (uint, error) {
 	if err != nil {
 		return 0, errors.Wrap(err, "error querying for attendees count")
 	}
+	defer result.Close()
 	if !result.Next() {
 		return 0, errors.New("unexpected SQL result")
 	}
@@ -67,29 +119,34 @@ func (s *SQLStorage) CountAttendees() (uint, error) {
 	return count, nil
 }
 
-func (s *SQLStorage) CreateAttendeesTable() error {
-	stmt, err := s.db.Prepare(createAttendeesTableStatement)
-	if err != nil {
-		return errors.Wrap(err, "error preparing attendees table creation")
-	}
-	_, err = stmt.Exec()
+func (s *SQLStorage) UpsertAttendee(attendee *models.Attendee) (interfaces.UpsertResult, error) {
+	result, err := s.upsert(attendeeExistsQuery, []interface{}{attendee.UserID()}, upsertAttendeeStatement, attendeeColumnValues(attendee)...)
 	if err != nil {
-		return errors.Wrap(err, "error creating attendees table")
+		return result, errors.Wrap(err, "error upserting attendee")
 	}
-	return nil
+	return result, nil
 }
 
-func (s *SQLStorage) UpsertAttendee(attendee *models.Attendee) error {
-	// TODO: Use a transaction to make this more elegant
-	if err := s.CreateAttendee(attendee); err == nil {
-		// This was a new entry, exit with no error
-		return nil
+func profileURLString(profileURL *url.URL) string {
+	if profileURL == nil {
+		return ""
 	}
-	// If we error on creation, try update in case it already exists
-	if err := s.UpdateAttendee(attendee); err != nil {
-		return errors.Wrap(err, "error upserting attendee")
+	return profileURL.String()
+}
+
+// attendeeColumnValues returns the values for insertAttendeeStatement.
+func attendeeColumnValues(attendee *models.Attendee) []interface{} {
+	return []interface{}{
+		attendee.PreferredName(),
+		attendee.LegalName(),
+		attendee.UserID(),
+		profileURLString(attendee.ProfileURL()),
+		attendee.IsHost(),
+		sqlTimestampOrNull(attendee.JoinedDate()),
+		attendee.Email(),
+		nameKey(attendee.PreferredName()),
+		nameKey(attendee.LegalName()),
 	}
-	return nil
 }
 
 func scanAttendeeFromRow(rows *sql.Rows) (*models.Attendee, error) {
@@ -98,19 +155,22 @@ func scanAttendeeFromRow(rows *sql.Rows) (*models.Attendee, error) {
 	var user_id string
 	var profile_url string
 	var is_host bool
-	var joined_date string
-	rows.Scan(&preferred_name, &legal_name, &user_id, &profile_url, &is_host, &joined_date)
-
-	joinDate, err := time.Parse(sqlTimestampFormat, joined_date)
+	var joined_date nullTimestamp
+	var email string
+	err := rows.Scan(&preferred_name, &legal_name, &user_id, &profile_url, &is_host, &joined_date, &email)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing SQL timestamp %#v", joined_date)
+		return nil, errors.Wrap(err, "error scanning row")
 	}
+
 	profileURL, err := url.Parse(profile_url)
-	return models.NewAttendee(preferred_name, legal_name, user_id, profileURL, &joinDate, is_host), nil
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing profile URL %#v", profile_url)
+	}
+	return models.NewAttendee(preferred_name, legal_name, user_id, profileURL, joined_date.Ptr(), is_host).WithEmail(email), nil
 }
 
 func (s *SQLStorage) FetchAttendee(userID string) (*models.Attendee, error) {
-	stmt, err := s.db.Prepare(selectAttendeeStatement)
+	stmt, err := s.q.Prepare(selectAttendeeStatement)
 	if err != nil {
 		return nil, errors.Wrap(err, "error while preparing select statement")
 	}
@@ -129,11 +189,11 @@ func (s *SQLStorage) FetchAttendee(userID string) (*models.Attendee, error) {
 }
 
 func (s *SQLStorage) CreateAttendee(attendee *models.Attendee) error {
-	stmt, err := s.db.Prepare(insertAttendeeStatement)
+	stmt, err := s.q.Prepare(insertAttendeeStatement)
 	if err != nil {
 		return errors.Wrap(err, "error while preparing insert statement")
 	}
-	_, err = stmt.Exec(attendee.PreferredName(), attendee.LegalName(), attendee.UserID(), attendee.ProfileURL().String(), attendee.IsHost(), attendee.JoinedDate().Format(sqlTimestampFormat))
+	_, err = stmt.Exec(attendeeColumnValues(attendee)...)
 	if err != nil {
 		return errors.Wrap(err, "error while executing insert statement")
 
@@ -142,12 +202,11 @@ func (s *SQLStorage) CreateAttendee(attendee *models.Attendee) error {
 }
 
 func (s *SQLStorage) UpdateAttendee(attendee *models.Attendee) error {
-	stmt, err := s.db.Prepare(updateAttendeeStatement)
+	stmt, err := s.q.Prepare(updateAttendeeStatement)
 	if err != nil {
 		return errors.Wrap(err, "error while preparing update statement")
 	}
-	joinDate := attendee.JoinedDate().Format(sqlTimestampFormat)
-	_, err = stmt.Exec(attendee.PreferredName(), attendee.LegalName(), attendee.ProfileURL().String(), attendee.IsHost(), joinDate, attendee.UserID())
+	_, err = stmt.Exec(attendee.PreferredName(), attendee.LegalName(), profileURLString(attendee.ProfileURL()), attendee.IsHost(), sqlTimestampOrNull(attendee.JoinedDate()), attendee.Email(), nameKey(attendee.PreferredName()), nameKey(attendee.LegalName()), attendee.UserID())
 	if err != nil {
 		return errors.Wrap(err, "error while executing update statement")
 
@@ -155,15 +214,23 @@ func (s *SQLStorage) UpdateAttendee(attendee *models.Attendee) error {
 	return nil
 }
 
+// DeleteAttendee deletes the attendee along with their attendances, so
+// they don't come back if the user ID is used again. Aliases of merged
+// attendees are kept.
 func (s *SQLStorage) DeleteAttendee(userID string) error {
-	stmt, err := s.db.Prepare(deleteAttendeeStatement)
-	if err != nil {
-		return errors.Wrap(err, "error while preparing delete statement")
-	}
-	_, err = stmt.Exec(userID)
-	if err != nil {
-		return errors.Wrap(err, "error while executing delete statement")
+	return s.withTx(func(tx *SQLStorage) error {
+		if _, err := tx.q.Exec(deleteAttendeeRowsStatement, userID); err != nil {
+			return errors.Wrap(err, "error while deleting the attendee's attendances")
+		}
+		stmt, err := tx.q.Prepare(deleteAttendeeStatement)
+		if err != nil {
+			return errors.Wrap(err, "error while preparing delete statement")
+		}
+		_, err = stmt.Exec(userID)
+		if err != nil {
+			return errors.Wrap(err, "error while executing delete statement")
 
-	}
-	return nil
+		}
+		return nil
+	})
 }
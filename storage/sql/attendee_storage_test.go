@@ -1 +1,33 @@
 package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestUpsertAttendeeReportsResult(t *testing.T) {
+	storage, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	attendee := models.NewAttendee("Dubie", "", "user 2", nil, nil, false)
+	result, err := storage.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Inserted, result)
+
+	result, err = storage.UpsertAttendee(attendee)
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Unchanged, result)
+
+	result, err = storage.UpsertAttendee(models.NewAttendee("Dubie", "The Dubester", "user 2", nil, nil, false))
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.Updated, result)
+
+	fetched, err := storage.FetchAttendee("user 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "The Dubester", fetched.LegalName())
+	assert.Nil(t, fetched.JoinedDate())
+}
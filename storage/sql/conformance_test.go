@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/alexthemitchell/community-attendance/storage/conformance"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (interfaces.Store, func()) {
+		return newTestStorage(t)
+	})
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/pkg/errors"
+)
+
+// dialect captures the differences between the SQL databases SQLStorage can
+// run against. Queries are written with "?" placeholders and upsert guards
+// use "%[1]s" for the null-safe inequality operator.
+type dialect struct {
+	name string
+	// distinctFrom is the null-safe "not equal" operator.
+	distinctFrom string
+	// unlimited is the LIMIT that returns every row, which SQLite requires
+	// before an OFFSET.
+	unlimited string
+	// wrap adapts a connection or transaction to the dialect's placeholders.
+	wrap func(q queryer) queryer
+}
+
+var (
+	sqliteDialect = &dialect{
+		name:         "sqlite",
+		distinctFrom: "IS NOT",
+		unlimited:    "-1",
+		wrap:         func(q queryer) queryer { return q },
+	}
+	postgresDialect = &dialect{
+		name:         "postgres",
+		distinctFrom: "IS DISTINCT FROM",
+		unlimited:    "ALL",
+		wrap:         func(q queryer) queryer { return numberedPlaceholders{q} },
+	}
+)
+
+// upsert fills the dialect's inequality operator into an upsert statement.
+func (d *dialect) upsert(statement string) string {
+	return fmt.Sprintf(statement, d.distinctFrom)
+}
+
+// numberedPlaceholders rewrites "?" placeholders to PostgreSQL's "$1", "$2"...
+type numberedPlaceholders struct {
+	q queryer
+}
+
+func rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (p numberedPlaceholders) Prepare(query string) (*sql.Stmt, error) {
+	return p.q.Prepare(rebind(query))
+}
+
+func (p numberedPlaceholders) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return p.q.Exec(rebind(query), args...)
+}
+
+func (p numberedPlaceholders) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return p.q.Query(rebind(query), args...)
+}
+
+func (p numberedPlaceholders) QueryRow(query string, args ...interface{}) *sql.Row {
+	return p.q.QueryRow(rebind(query), args...)
+}
+
+// nullTimestamp scans a timestamp column regardless of whether the driver
+// returns it as text (SQLite) or as a time.Time (PostgreSQL).
+type nullTimestamp struct {
+	Time  gotime.Time
+	Valid bool
+}
+
+func (n *nullTimestamp) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		n.Time, n.Valid = gotime.Time{}, false
+		return nil
+	case gotime.Time:
+		n.Time, n.Valid = gotime.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), 0, gotime.UTC), true
+		return nil
+	case []byte:
+		return n.parse(string(v))
+	case string:
+		return n.parse(v)
+	}
+	return errors.Errorf("cannot scan %T into a timestamp", value)
+}
+
+func (n *nullTimestamp) parse(value string) error {
+	t, err := gotime.Parse(sqlTimestampFormat, value)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing SQL timestamp %#v", value)
+	}
+	n.Time, n.Valid = t, true
+	return nil
+}
+
+// Ptr returns the scanned time, or nil for NULL.
+func (n nullTimestamp) Ptr() *gotime.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := n.Time
+	return &t
+}
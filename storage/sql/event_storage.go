@@ -2,30 +2,53 @@ package storage
 
 import (
 	"database/sql"
-	"fmt"
 	gotime "time"
 
 	"github.com/pkg/errors"
 
 	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
 )
 
 const (
-	countEventsQuery           = "SELECT COUNT(*) FROM events"
-	createEventsTableStatement = "CREATE TABLE IF NOT EXISTS events (name varchar(255) not null, time DATETIME not null, id varchar(36) primary key not null, UNIQUE(id))"
-	insertEventStatement       = "INSERT INTO events(name, time, id) VALUES (?,?,?)"
-	deleteEventStatement       = "DELETE FROM events WHERE id=?"
-	selectEventStatement       = "SELECT name, id, time FROM events WHERE id=?"
-	selectAllEventsStatement   = "SELECT name, id, time FROM events"
-	updateEventStatement       = "UPDATE events SET name=?, time=? WHERE id=?"
+	countEventsQuery                = "SELECT COUNT(*) FROM events"
+	createEventsTableStatement      = "CREATE TABLE IF NOT EXISTS events (name varchar(255) not null, time DATETIME not null, id varchar(36) primary key not null, UNIQUE(id))"
+	insertEventStatement            = "INSERT INTO events(name, time, id, venue_name, venue_address, capacity, group_name, series_id, duration_seconds, time_zone, source_id, name_key) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)"
+	deleteEventStatement            = "DELETE FROM events WHERE id=?"
+	deleteEventAttendancesStatement = "DELETE FROM attendances WHERE event_id=?"
+	deleteEventImportsStatement     = "DELETE FROM imports WHERE event_id=?"
+	selectEventColumns              = "name, id, time, venue_name, venue_address, capacity, group_name, series_id, duration_seconds, time_zone, source_id"
+	selectEventStatement            = "SELECT " + selectEventColumns + " FROM events WHERE id=?"
+	selectAllEventsStatement        = "SELECT " + selectEventColumns + " FROM events"
+	updateEventStatement            = "UPDATE events SET name=?, time=?, venue_name=?, venue_address=?, capacity=?, group_name=?, series_id=?, duration_seconds=?, time_zone=?, source_id=?, name_key=? WHERE id=?"
+	eventExistsQuery                = "SELECT COUNT(*) FROM events WHERE id=?"
+	upsertEventStatement            = insertEventStatement + " ON CONFLICT(id) DO UPDATE SET name=excluded.name, time=excluded.time, venue_name=excluded.venue_name, venue_address=excluded.venue_address, capacity=excluded.capacity, group_name=excluded.group_name, series_id=excluded.series_id, duration_seconds=excluded.duration_seconds, time_zone=excluded.time_zone, source_id=excluded.source_id, name_key=excluded.name_key" +
+		" WHERE events.name %[1]s excluded.name OR events.time %[1]s excluded.time OR events.venue_name %[1]s excluded.venue_name OR events.venue_address %[1]s excluded.venue_address OR events.capacity %[1]s excluded.capacity" +
+		" OR events.group_name %[1]s excluded.group_name OR events.series_id %[1]s excluded.series_id OR events.duration_seconds %[1]s excluded.duration_seconds OR events.time_zone %[1]s excluded.time_zone OR events.source_id %[1]s excluded.source_id"
+	createEventSourceIDIndexStatement = "CREATE INDEX events_source_id ON events(source_id)"
 )
 
+// addEventDetailsStatements add a column for each of the event details,
+// one statement per column since SQLite adds them one at a time, and index
+// the source ID for finding previously imported events.
+var addEventDetailsStatements = []string{
+	"ALTER TABLE events ADD COLUMN venue_name varchar(255) not null default ''",
+	"ALTER TABLE events ADD COLUMN venue_address varchar(1000) not null default ''",
+	"ALTER TABLE events ADD COLUMN capacity integer not null default 0",
+	"ALTER TABLE events ADD COLUMN group_name varchar(255) not null default ''",
+	"ALTER TABLE events ADD COLUMN series_id varchar(255) not null default ''",
+	"ALTER TABLE events ADD COLUMN duration_seconds integer not null default 0",
+	"ALTER TABLE events ADD COLUMN time_zone varchar(64) not null default ''",
+	"ALTER TABLE events ADD COLUMN source_id varchar(255) not null default ''",
+	createEventSourceIDIndexStatement,
+}
+
 var (
-	ErrNoEntryWithEventID = errors.New("no entry exists with the given event identifier")
+	ErrNoEntryWithEventID = interfaces.ErrNoEntryWithEventID
 )
 
 func (s *SQLStorage) GetAllEvents() ([]*models.Event, error) {
-	stmt, err := s.db.Prepare(selectAllEventsStatement)
+	stmt, err := s.q.Prepare(selectAllEventsStatement)
 	if err != nil {
 		return nil, errors.Wrap(err, "error preparing get all events query")
 	}
@@ -46,8 +69,47 @@ func (s *SQLStorage) GetAllEvents() ([]*models.Event, error) {
 	return events, nil
 }
 
+func (s *SQLStorage) QueryEvents(options interfaces.EventQuery) ([]*models.Event, error) {
+	if err := options.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error querying events")
+	}
+	q := &query{base: selectAllEventsStatement}
+	q.timeRange("time", options.From, options.To)
+	if options.NameContains != "" {
+		q.where(`name_key LIKE ? ESCAPE '\'`, likePattern(nameKey(options.NameContains)))
+	}
+	if options.SourceID != "" {
+		q.where("source_id = ?", options.SourceID)
+	}
+	key, descending := options.SortKey()
+	switch key {
+	case interfaces.SortEventsByTime:
+		q.order(descending, "time")
+	case interfaces.SortEventsByName:
+		q.order(descending, "name_key")
+	}
+	q.order(descending, "id")
+	q.page(s.dialect, options.Limit, options.Offset)
+
+	rows, err := s.q.Query(q.String(), q.args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying events")
+	}
+	defer rows.Close()
+
+	var events []*models.Event
+	for rows.Next() {
+		event, err := scanEventFromRow(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning event from row")
+		}
+		events = append(events, event)
+	}
+	return events, errors.Wrap(rows.Err(), "error reading events")
+}
+
 func (s *SQLStorage) CountEvents() (uint, error) {
-	stmt, err := s.db.Prepare(countEventsQuery)
+	stmt, err := s.q.Prepare(countEventsQuery)
 	if err != nil {
 		return 0, errors.Wrap(err, "error preparing events count query")
 	}
@@ -55,6 +117,7 @@ func (s *SQLStorage) CountEvents() (uint, error) {
 	if err != nil {
 		return 0, errors.Wrap(err, "error querying for events count")
 	}
+	defer result.Close()
 	if !result.Next() {
 		return 0, errors.New("unexpected SQL result")
 	}
@@ -64,53 +127,70 @@ func (s *SQLStorage) CountEvents() (uint, error) {
 	return count, nil
 }
 
-func (s *SQLStorage) CreateEventsTable() error {
-	stmt, err := s.db.Prepare(createEventsTableStatement)
-	if err != nil {
-		return errors.Wrap(err, "error preparing events table creation")
+func (s *SQLStorage) UpsertEvent(event *models.Event) (interfaces.UpsertResult, error) {
+	if event.Time() == nil {
+		return interfaces.Unchanged, errors.New("error upserting event: event has no time")
 	}
-	_, err = stmt.Exec()
+	result, err := s.upsert(eventExistsQuery, []interface{}{event.ID()}, upsertEventStatement, eventColumnValues(event)...)
 	if err != nil {
-		return errors.Wrap(err, "error creating events table")
+		return result, errors.Wrap(err, "error upserting event")
 	}
-	return nil
+	return result, nil
 }
 
-func (s *SQLStorage) UpsertEvent(event *models.Event) error {
-	// TODO: Use a transaction to make this more elegant
-	if err := s.CreateEvent(event); err == nil {
-		// This was a new entry, exit with no error
-		fmt.Println("Created Event")
-		return nil
-	} else {
-		fmt.Println(err)
+// eventColumnValues returns the values for insertEventStatement.
+func eventColumnValues(event *models.Event) []interface{} {
+	d := event.Details()
+	return []interface{}{
+		event.Name(),
+		sqlTimestampOrNull(event.Time()),
+		event.ID(),
+		d.VenueName,
+		d.VenueAddress,
+		d.Capacity,
+		d.Group,
+		d.SeriesID,
+		int64(d.Duration / gotime.Second),
+		d.TimeZone,
+		d.SourceID,
+		nameKey(event.Name()),
 	}
-	// If we error on creation, try update in case it already exists
-	if err := s.UpdateEvent(event); err != nil {
-		return errors.Wrap(err, "error upserting event")
+}
+
+// eventFields receives the columns of selectEventColumns.
+type eventFields struct {
+	name            string
+	id              string
+	time            nullTimestamp
+	details         models.EventDetails
+	durationSeconds int64
+}
+
+func (f *eventFields) dest() []interface{} {
+	return []interface{}{
+		&f.name, &f.id, &f.time,
+		&f.details.VenueName, &f.details.VenueAddress, &f.details.Capacity, &f.details.Group,
+		&f.details.SeriesID, &f.durationSeconds, &f.details.TimeZone, &f.details.SourceID,
 	}
-	fmt.Println("Updated Event")
-	return nil
+}
+
+func (f *eventFields) model() *models.Event {
+	details := f.details
+	details.Duration = gotime.Duration(f.durationSeconds) * gotime.Second
+	return models.NewEvent(f.name, f.id, f.time.Ptr()).WithDetails(details)
 }
 
 func scanEventFromRow(rows *sql.Rows) (*models.Event, error) {
-	var id string
-	var name string
-	var time string
-	err := rows.Scan(&name, &id, &time)
+	var fields eventFields
+	err := rows.Scan(fields.dest()...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
-
-	eventTime, err := gotime.Parse(sqlTimestampFormat, time)
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing SQL timestamp %#v", time)
-	}
-	return models.NewEvent(name, id, &eventTime), nil
+	return fields.model(), nil
 }
 
 func (s *SQLStorage) FetchEvent(eventID string) (*models.Event, error) {
-	stmt, err := s.db.Prepare(selectEventStatement)
+	stmt, err := s.q.Prepare(selectEventStatement)
 	if err != nil {
 		return nil, errors.Wrap(err, "error while preparing select statement")
 	}
@@ -129,12 +209,11 @@ func (s *SQLStorage) FetchEvent(eventID string) (*models.Event, error) {
 }
 
 func (s *SQLStorage) CreateEvent(event *models.Event) error {
-	stmt, err := s.db.Prepare(insertEventStatement)
+	stmt, err := s.q.Prepare(insertEventStatement)
 	if err != nil {
 		return errors.Wrap(err, "error while preparing insert statement")
 	}
-	eventTime := event.Time().Format(sqlTimestampFormat)
-	_, err = stmt.Exec(event.Name(), eventTime, event.ID())
+	_, err = stmt.Exec(eventColumnValues(event)...)
 	if err != nil {
 		return errors.Wrap(err, "error while executing insert statement")
 
@@ -143,12 +222,14 @@ func (s *SQLStorage) CreateEvent(event *models.Event) error {
 }
 
 func (s *SQLStorage) UpdateEvent(event *models.Event) error {
-	stmt, err := s.db.Prepare(updateEventStatement)
+	stmt, err := s.q.Prepare(updateEventStatement)
 	if err != nil {
 		return errors.Wrap(err, "error while preparing update statement")
 	}
-	eventTime := event.Time().Format(sqlTimestampFormat)
-	_, err = stmt.Exec(event.Name(), eventTime, event.ID())
+	values := eventColumnValues(event)
+	// The ID moves from third to last for the WHERE clause.
+	args := append(append(values[:2:2], values[3:]...), event.ID())
+	_, err = stmt.Exec(args...)
 	if err != nil {
 		return errors.Wrap(err, "error while executing update statement")
 
@@ -156,15 +237,24 @@ func (s *SQLStorage) UpdateEvent(event *models.Event) error {
 	return nil
 }
 
+// DeleteEvent deletes the event along with its attendances and import
+// history, so none of them come back if the ID is used again.
 func (s *SQLStorage) DeleteEvent(eventID string) error {
-	stmt, err := s.db.Prepare(deleteEventStatement)
-	if err != nil {
-		return errors.Wrap(err, "error while preparing delete statement")
-	}
-	_, err = stmt.Exec(eventID)
-	if err != nil {
-		return errors.Wrap(err, "error while executing delete statement")
+	return s.withTx(func(tx *SQLStorage) error {
+		for _, statement := range []string{deleteEventAttendancesStatement, deleteEventImportsStatement} {
+			if _, err := tx.q.Exec(statement, eventID); err != nil {
+				return errors.Wrap(err, "error while deleting the event's rows")
+			}
+		}
+		stmt, err := tx.q.Prepare(deleteEventStatement)
+		if err != nil {
+			return errors.Wrap(err, "error while preparing delete statement")
+		}
+		_, err = stmt.Exec(eventID)
+		if err != nil {
+			return errors.Wrap(err, "error while executing delete statement")
 
-	}
-	return nil
+		}
+		return nil
+	})
 }
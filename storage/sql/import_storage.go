@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+const (
+	createImportsTableStatement         = "CREATE TABLE imports (id integer primary key, event_id varchar(36) not null, file_name varchar(255) not null, sha256 varchar(64) not null, imported_at DATETIME not null, records integer not null)"
+	postgresCreateImportsTableStatement = "CREATE TABLE imports (id serial primary key, event_id varchar(36) not null, file_name varchar(255) not null, sha256 varchar(64) not null, imported_at TIMESTAMP not null, records integer not null)"
+	insertImportStatement               = "INSERT INTO imports(event_id, file_name, sha256, imported_at, records) VALUES (?,?,?,?,?)"
+	selectImportsForEventQuery          = "SELECT event_id, file_name, sha256, imported_at, records FROM imports WHERE event_id=? ORDER BY imported_at, id"
+)
+
+func (s *SQLStorage) RecordImport(record *models.Import) error {
+	importedAt := record.ImportedAt()
+	_, err := s.q.Exec(insertImportStatement, record.EventID(), record.FileName(), record.SHA256(),
+		sqlTimestampOrNull(&importedAt), record.Records())
+	return errors.Wrap(err, "error recording import")
+}
+
+func (s *SQLStorage) GetImportsForEvent(eventID string) ([]*models.Import, error) {
+	rows, err := s.q.Query(selectImportsForEventQuery, eventID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error querying imports of event %#v", eventID)
+	}
+	defer rows.Close()
+
+	var imports []*models.Import
+	for rows.Next() {
+		var eventID, fileName, sha256 string
+		var importedAt nullTimestamp
+		var records int
+		if err := rows.Scan(&eventID, &fileName, &sha256, &importedAt, &records); err != nil {
+			return nil, errors.Wrap(err, "error scanning import")
+		}
+		imports = append(imports, models.NewImport(eventID, fileName, sha256, importedAt.Time, records))
+	}
+	return imports, errors.Wrap(rows.Err(), "error reading imports")
+}
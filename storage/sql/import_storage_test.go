@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+)
+
+func TestGetImportsForEventOrder(t *testing.T) {
+	storage, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	later := time.Date(2019, time.March, 15, 9, 0, 0, 0, time.UTC)
+	earlier := later.Add(-time.Hour)
+	for _, record := range []*models.Import{
+		models.NewImport("event-1", "second.tsv", "b", later, 2),
+		models.NewImport("event-1", "first.tsv", "a", earlier, 1),
+		models.NewImport("event-2", "other.tsv", "c", earlier, 1),
+		models.NewImport("event-1", "third.tsv", "b", later, 3),
+	} {
+		assert.NoError(t, storage.RecordImport(record))
+	}
+
+	imports, err := storage.GetImportsForEvent("event-1")
+	assert.NoError(t, err)
+	var names []string
+	for _, record := range imports {
+		names = append(names, record.FileName())
+	}
+	// Imports recorded in the same second stay in the order recorded.
+	assert.Equal(t, []string{"first.tsv", "second.tsv", "third.tsv"}, names)
+	assert.Equal(t, earlier, imports[0].ImportedAt())
+}
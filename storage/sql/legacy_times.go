@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+// Before schema version 8, times were stored as wall clock times labeled
+// UTC: event times as given on the command line, RSVP times as the export
+// showed them and check-in times in the local zone of whoever checked in.
+// Migration 8 marks databases that already hold events, so their times can
+// be reinterpreted once the zones they were written in are known.
+const (
+	legacyTimesVersion              = 8
+	createLegacyTimesTableStatement = "CREATE TABLE legacy_times (pending boolean not null)"
+	markLegacyTimesStatement        = "INSERT INTO legacy_times(pending) SELECT TRUE WHERE EXISTS (SELECT 1 FROM events)"
+	countLegacyTimesQuery           = "SELECT COUNT(*) FROM legacy_times"
+	clearLegacyTimesStatement       = "DELETE FROM legacy_times"
+)
+
+// HasLegacyTimes reports whether the database holds times written before
+// they were stored in UTC that haven't been converted yet.
+func (s *SQLStorage) HasLegacyTimes() (bool, error) {
+	version, err := s.SchemaVersion()
+	if err != nil || version < legacyTimesVersion {
+		return false, err
+	}
+	var count int
+	if err := s.q.QueryRow(countLegacyTimesQuery).Scan(&count); err != nil {
+		return false, errors.Wrap(err, "error checking for legacy times")
+	}
+	return count > 0, nil
+}
+
+// ConvertLegacyTimes runs convert in a transaction if the database holds
+// legacy times, then marks them converted so it never runs twice. It
+// returns whether convert ran.
+func (s *SQLStorage) ConvertLegacyTimes(convert func(tx interfaces.Store) error) (bool, error) {
+	converted := false
+	err := s.withTx(func(tx *SQLStorage) error {
+		pending, err := tx.HasLegacyTimes()
+		if err != nil || !pending {
+			return err
+		}
+		if err := convert(tx); err != nil {
+			return err
+		}
+		if _, err := tx.q.Exec(clearLegacyTimesStatement); err != nil {
+			return errors.Wrap(err, "error marking legacy times converted")
+		}
+		converted = true
+		return nil
+	})
+	return converted, err
+}
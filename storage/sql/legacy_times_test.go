@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestConvertLegacyTimes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attendance-legacy-times")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "test.db"))
+	assert.NoError(t, err)
+	storage, err := NewUnmigratedSQLStorage(db)
+	assert.NoError(t, err)
+	defer storage.Close()
+
+	assert.NoError(t, storage.Migrate(legacyTimesVersion-1))
+	pending, err := storage.HasLegacyTimes()
+	assert.NoError(t, err)
+	assert.False(t, pending)
+	// Write the old columns directly; the storage methods expect the
+	// latest schema.
+	_, err = db.Exec("INSERT INTO events(name, time, id) VALUES (?,?,?)", "Hack Night", "2019-03-14T18:30:00Z", "event-1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Migrate(LatestSchemaVersion()))
+	pending, err = storage.HasLegacyTimes()
+	assert.NoError(t, err)
+	assert.True(t, pending)
+
+	// A failed conversion leaves the times marked.
+	_, err = storage.ConvertLegacyTimes(func(tx interfaces.Store) error { return ErrNoAttendanceEntry })
+	assert.Error(t, err)
+	pending, err = storage.HasLegacyTimes()
+	assert.NoError(t, err)
+	assert.True(t, pending)
+
+	calls := 0
+	convert := func(tx interfaces.Store) error {
+		calls++
+		return nil
+	}
+	converted, err := storage.ConvertLegacyTimes(convert)
+	assert.NoError(t, err)
+	assert.True(t, converted)
+	converted, err = storage.ConvertLegacyTimes(convert)
+	assert.NoError(t, err)
+	assert.False(t, converted)
+	assert.Equal(t, 1, calls)
+}
+
+func TestNewDatabaseHasNoLegacyTimes(t *testing.T) {
+	storage, cleanup := newTestStorage(t)
+	defer cleanup()
+	pending, err := storage.HasLegacyTimes()
+	assert.NoError(t, err)
+	assert.False(t, pending)
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	gotime "time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	createSchemaVersionTableStatement         = "CREATE TABLE IF NOT EXISTS schema_version (version integer primary key not null, description varchar(255) not null, applied_at DATETIME not null)"
+	postgresCreateSchemaVersionTableStatement = "CREATE TABLE IF NOT EXISTS schema_version (version integer primary key not null, description varchar(255) not null, applied_at TIMESTAMP not null)"
+	selectSchemaVersionQuery                  = "SELECT COALESCE(MAX(version), 0) FROM schema_version"
+	insertSchemaVersionStatement              = "INSERT INTO schema_version(version, description, applied_at) VALUES (?,?,?)"
+)
+
+// Migration is a single, numbered change to the database schema. Migrations
+// are applied in order and each one is recorded in the schema_version table
+// once it has been applied. Statements holds the DDL for each dialect, keyed
+// by dialect name.
+type Migration struct {
+	Version     int
+	Description string
+	Statements  map[string][]string
+
+	// fill, if set, runs after the statements in the same transaction, for
+	// data changes that have to be made in Go.
+	fill func(tx *SQLStorage) error
+}
+
+// migrations must be kept in ascending version order and must never be
+// edited once released; add a new migration instead. The first three use
+// CREATE TABLE IF NOT EXISTS so that databases created before versioning
+// was introduced are adopted without error.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create attendees table",
+		Statements: map[string][]string{
+			"sqlite":   {createAttendeesTableStatement},
+			"postgres": {postgresCreateAttendeesTableStatement},
+		},
+	},
+	{
+		Version:     2,
+		Description: "create events table",
+		Statements: map[string][]string{
+			"sqlite":   {createEventsTableStatement},
+			"postgres": {postgresCreateEventsTableStatement},
+		},
+	},
+	{
+		Version:     3,
+		Description: "create attendances table",
+		Statements: map[string][]string{
+			"sqlite":   {createAttendancesTableStatement},
+			"postgres": {postgresCreateAttendancesTableStatement},
+		},
+	},
+	{
+		Version:     4,
+		Description: "add check-ins to attendances",
+		Statements: map[string][]string{
+			"sqlite":   {addCheckInStatusColumnStatement, addCheckInTimeColumnStatement, addCheckedInByColumnStatement},
+			"postgres": {addCheckInStatusColumnStatement, postgresAddCheckInTimeColumnStatement, addCheckedInByColumnStatement},
+		},
+	},
+	{
+		Version:     5,
+		Description: "add attendee emails and aliases",
+		Statements: map[string][]string{
+			"sqlite":   {addAttendeeEmailColumnStatement, createAttendeeAliasesTableStatement},
+			"postgres": {addAttendeeEmailColumnStatement, createAttendeeAliasesTableStatement},
+		},
+	},
+	{
+		Version:     6,
+		Description: "add event details",
+		Statements: map[string][]string{
+			"sqlite":   addEventDetailsStatements,
+			"postgres": addEventDetailsStatements,
+		},
+	},
+	{
+		Version:     7,
+		Description: "add cancelled RSVPs and import history",
+		Statements: map[string][]string{
+			"sqlite":   {addCancelledColumnStatement, createImportsTableStatement},
+			"postgres": {addCancelledColumnStatement, postgresCreateImportsTableStatement},
+		},
+	},
+	{
+		Version:     legacyTimesVersion,
+		Description: "store times in UTC, marking existing times for conversion",
+		Statements: map[string][]string{
+			"sqlite":   {createLegacyTimesTableStatement, markLegacyTimesStatement},
+			"postgres": {createLegacyTimesTableStatement, markLegacyTimesStatement},
+		},
+	},
+	{
+		Version:     nameKeysVersion,
+		Description: "add case-folded name columns",
+		Statements: map[string][]string{
+			"sqlite":   {addEventNameKeyColumnStatement, addAttendeePreferredNameKeyStatement, addAttendeeLegalNameKeyStatement},
+			"postgres": {addEventNameKeyColumnStatement, addAttendeePreferredNameKeyStatement, addAttendeeLegalNameKeyStatement},
+		},
+		fill: fillNameKeys,
+	},
+}
+
+// Migrations returns every known migration in the order it is applied.
+func Migrations() []Migration {
+	return append([]Migration(nil), migrations...)
+}
+
+// LatestSchemaVersion is the version a database is at once every known
+// migration has been applied.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+func (s *SQLStorage) createSchemaVersionTable() error {
+	statement := createSchemaVersionTableStatement
+	if s.dialect == postgresDialect {
+		statement = postgresCreateSchemaVersionTableStatement
+	}
+	_, err := s.q.Exec(statement)
+	if err != nil {
+		return errors.Wrap(err, "error creating schema_version table")
+	}
+	return nil
+}
+
+// SchemaVersion returns the version of the most recently applied migration,
+// or 0 for an empty database.
+func (s *SQLStorage) SchemaVersion() (int, error) {
+	var version int
+	err := s.q.QueryRow(selectSchemaVersionQuery).Scan(&version)
+	if err != nil {
+		return 0, errors.Wrap(err, "error querying schema version")
+	}
+	return version, nil
+}
+
+// Migrate applies every migration after the current schema version up to and
+// including target. Each migration runs in its own transaction. Downgrades
+// are not supported.
+func (s *SQLStorage) Migrate(target int) error {
+	if target < 0 || target > LatestSchemaVersion() {
+		return errors.Errorf("unknown schema version %d (latest is %d)", target, LatestSchemaVersion())
+	}
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if target < current {
+		return errors.Errorf("cannot migrate down from schema version %d to %d", current, target)
+	}
+	for _, m := range migrations {
+		if m.Version <= current || m.Version > target {
+			continue
+		}
+		if err := s.applyMigration(m); err != nil {
+			return errors.Wrapf(err, "error applying migration %d (%s)", m.Version, m.Description)
+		}
+		log.WithField("version", m.Version).Debugf("applied migration: %s", m.Description)
+	}
+	return nil
+}
+
+func (s *SQLStorage) applyMigration(m Migration) error {
+	return s.withTx(func(tx *SQLStorage) error {
+		statements, ok := m.Statements[s.dialect.name]
+		if !ok {
+			return errors.Errorf("migration has no statements for %s", s.dialect.name)
+		}
+		for _, statement := range statements {
+			if _, err := tx.q.Exec(statement); err != nil {
+				return errors.Wrap(err, "error executing migration statement")
+			}
+		}
+		if m.fill != nil {
+			if err := m.fill(tx); err != nil {
+				return err
+			}
+		}
+		_, err := tx.q.Exec(insertSchemaVersionStatement, m.Version, m.Description, gotime.Now().UTC().Format(sqlTimestampFormat))
+		if err != nil {
+			return errors.Wrap(err, "error recording schema version")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func TestMigrateStepByStep(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attendance-migrations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "test.db"))
+	assert.NoError(t, err)
+
+	storage, err := NewUnmigratedSQLStorage(db)
+	assert.NoError(t, err)
+	defer storage.Close()
+
+	version, err := storage.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	assert.NoError(t, storage.Migrate(1))
+	version, err = storage.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	assert.NoError(t, storage.Migrate(LatestSchemaVersion()))
+	version, err = storage.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	assert.Error(t, storage.Migrate(1))
+	assert.Error(t, storage.Migrate(LatestSchemaVersion()+1))
+}
+
+func TestMigrateAdoptsUnversionedDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attendance-migrations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "test.db"))
+	assert.NoError(t, err)
+
+	_, err = db.Exec(createAttendeesTableStatement)
+	assert.NoError(t, err)
+	_, err = db.Exec(createEventsTableStatement)
+	assert.NoError(t, err)
+
+	storage, err := NewSQLStorage(db)
+	assert.NoError(t, err)
+	defer storage.Close()
+	version, err := storage.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+}
+
+func TestMigrateFillsNameKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attendance-migrations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "test.db"))
+	assert.NoError(t, err)
+
+	storage, err := NewUnmigratedSQLStorage(db)
+	assert.NoError(t, err)
+	defer storage.Close()
+	assert.NoError(t, storage.Migrate(nameKeysVersion-1))
+	_, err = db.Exec("INSERT INTO events(name, time, id) VALUES (?,?,?)", "ÉCLAIR SOCIAL", "2019-03-14T18:30:00Z", "event-1")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO attendees(preferred_name, legal_name, user_id, profile_url, is_host) VALUES (?,?,?,'',FALSE)", "Émile", "", "user-1")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO attendees(preferred_name, legal_name, user_id, profile_url, is_host) VALUES (?,?,?,'',FALSE)", "ébène", "", "user-2")
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Migrate(LatestSchemaVersion()))
+	events, err := storage.QueryEvents(interfaces.EventQuery{NameContains: "éclair"})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "event-1", events[0].ID())
+	}
+	attendees, err := storage.QueryAttendees(interfaces.AttendeeQuery{Sort: interfaces.SortAttendeesByName})
+	assert.NoError(t, err)
+	if assert.Len(t, attendees, 2) {
+		assert.Equal(t, "user-2", attendees[0].UserID())
+		assert.Equal(t, "user-1", attendees[1].UserID())
+	}
+}
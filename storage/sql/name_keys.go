@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SQLite's LOWER only folds ASCII letters, so names are folded in Go and
+// saved beside the original in a key column that searches and sorts use.
+// Migration 9 adds the key columns and fills them for existing rows.
+const (
+	nameKeysVersion                      = 9
+	addEventNameKeyColumnStatement       = "ALTER TABLE events ADD COLUMN name_key varchar(255) not null default ''"
+	addAttendeePreferredNameKeyStatement = "ALTER TABLE attendees ADD COLUMN preferred_name_key varchar(255) not null default ''"
+	addAttendeeLegalNameKeyStatement     = "ALTER TABLE attendees ADD COLUMN legal_name_key varchar(255) not null default ''"
+	selectEventNamesQuery                = "SELECT id, name FROM events"
+	updateEventNameKeyStatement          = "UPDATE events SET name_key=? WHERE id=?"
+	selectAttendeeNamesQuery             = "SELECT user_id, preferred_name, legal_name FROM attendees"
+	updateAttendeeNameKeysStatement      = "UPDATE attendees SET preferred_name_key=?, legal_name_key=? WHERE user_id=?"
+)
+
+// nameKey folds the case of name for searching and sorting.
+func nameKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// fillNameKeys sets the key columns of every existing event and attendee.
+func fillNameKeys(tx *SQLStorage) error {
+	events := map[string]string{}
+	err := tx.eachRow(selectEventNamesQuery, func(rows *sql.Rows) error {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		events[id] = name
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "error reading event names")
+	}
+	for id, name := range events {
+		if _, err := tx.q.Exec(updateEventNameKeyStatement, nameKey(name), id); err != nil {
+			return errors.Wrapf(err, "error setting name key of event %#v", id)
+		}
+	}
+
+	attendees := map[string][2]string{}
+	err = tx.eachRow(selectAttendeeNamesQuery, func(rows *sql.Rows) error {
+		var userID string
+		var preferredName, legalName sql.NullString
+		if err := rows.Scan(&userID, &preferredName, &legalName); err != nil {
+			return err
+		}
+		attendees[userID] = [2]string{preferredName.String, legalName.String}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "error reading attendee names")
+	}
+	for userID, names := range attendees {
+		if _, err := tx.q.Exec(updateAttendeeNameKeysStatement, nameKey(names[0]), nameKey(names[1]), userID); err != nil {
+			return errors.Wrapf(err, "error setting name keys of attendee %#v", userID)
+		}
+	}
+	return nil
+}
+
+// eachRow runs query and calls scan for each row. The rows are closed before
+// it returns, so scan must not run other statements on the same transaction.
+func (s *SQLStorage) eachRow(query string, scan func(rows *sql.Rows) error) error {
+	rows, err := s.q.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/storage/conformance"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+const defaultTestPostgresDSN = "postgres://localhost/attendance_test?sslmode=disable"
+
+// newTestPostgresStorage connects to the database named by
+// ATTENDANCE_TEST_POSTGRES_DSN (or a local default), wiping it first. The
+// test is skipped when no server is reachable.
+func newTestPostgresStorage(t *testing.T) (*SQLStorage, func()) {
+	dsn := os.Getenv("ATTENDANCE_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		dsn = defaultTestPostgresDSN
+	}
+	db, err := sql.Open("postgres", dsn)
+	assert.NoError(t, err)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		t.Skipf("no PostgreSQL server available at %s: %v", dsn, err)
+	}
+	// Dropping the whole schema removes every table a migration made, so
+	// each test starts from an empty database.
+	for _, statement := range []string{"DROP SCHEMA public CASCADE", "CREATE SCHEMA public"} {
+		_, err = db.Exec(statement)
+		assert.NoError(t, err)
+	}
+	storage, err := NewPostgresStorage(db)
+	assert.NoError(t, err)
+	return storage, storage.Close
+}
+
+func TestPostgresConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (interfaces.Store, func()) {
+		return newTestPostgresStorage(t)
+	})
+}
+
+func TestRebind(t *testing.T) {
+	assert.Equal(t, "UPDATE events SET name=$1, time=$2 WHERE id=$3", rebind("UPDATE events SET name=?, time=? WHERE id=?"))
+}
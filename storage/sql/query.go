@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"strings"
+	gotime "time"
+)
+
+// attendedCondition matches attendances that count as showing up: a
+// check-in or walk-in, or a yes RSVP to an event nobody's check-in was
+// recorded at. Its arguments are the CheckedIn and WalkIn statuses.
+const attendedCondition = "(a.check_in_status IN (?, ?) OR (a.rsvp AND NOT EXISTS " +
+	"(SELECT 1 FROM attendances c WHERE c.event_id = a.event_id AND c.check_in_status <> '')))"
+
+// query builds a SELECT from a fixed column list and table, adding
+// conditions, ordering and paging as a query's options require.
+type query struct {
+	base       string
+	conditions []string
+	orderBy    []string
+	args       []interface{}
+	limit      string
+}
+
+// where adds a condition, with "?" placeholders for args, that rows must
+// meet.
+func (q *query) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// timeRange restricts column to [from, to), skipping nil bounds.
+func (q *query) timeRange(column string, from, to *gotime.Time) {
+	if from != nil {
+		q.where(column+" >= ?", sqlTimestampOrNull(from))
+	}
+	if to != nil {
+		q.where(column+" < ?", sqlTimestampOrNull(to))
+	}
+}
+
+// order appends ORDER BY terms, each descending if descending is true.
+func (q *query) order(descending bool, terms ...string) {
+	for _, term := range terms {
+		if descending {
+			term += " DESC"
+		}
+		q.orderBy = append(q.orderBy, term)
+	}
+}
+
+// page limits the results to limit rows after skipping offset, where zero
+// means no limit or no offset.
+func (q *query) page(d *dialect, limit, offset int) {
+	switch {
+	case limit > 0 && offset > 0:
+		q.limit = " LIMIT ? OFFSET ?"
+		q.args = append(q.args, limit, offset)
+	case limit > 0:
+		q.limit = " LIMIT ?"
+		q.args = append(q.args, limit)
+	case offset > 0:
+		q.limit = " LIMIT " + d.unlimited + " OFFSET ?"
+		q.args = append(q.args, offset)
+	}
+}
+
+func (q *query) String() string {
+	var b strings.Builder
+	b.WriteString(q.base)
+	if len(q.conditions) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(q.conditions, " AND "))
+	}
+	if len(q.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	b.WriteString(q.limit)
+	return b.String()
+}
+
+// likePattern returns a LIKE pattern matching values that contain substring,
+// escaping LIKE's wildcards with a backslash.
+func likePattern(substring string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(substring)
+	return "%" + escaped + "%"
+}
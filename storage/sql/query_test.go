@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryString(t *testing.T) {
+	q := &query{base: "SELECT id FROM events"}
+	assert.Equal(t, "SELECT id FROM events", q.String())
+
+	from := time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)
+	q.timeRange("time", &from, nil)
+	q.where("name = ?", "Hack Night")
+	q.order(true, "time", "id")
+	q.page(sqliteDialect, 10, 20)
+	assert.Equal(t, "SELECT id FROM events WHERE time >= ? AND name = ? ORDER BY time DESC, id DESC LIMIT ? OFFSET ?", q.String())
+	assert.Equal(t, []interface{}{"2019-03-01T00:00:00Z", "Hack Night", 10, 20}, q.args)
+}
+
+func TestQueryOffsetWithoutLimit(t *testing.T) {
+	q := &query{base: "SELECT id FROM events"}
+	q.page(sqliteDialect, 0, 5)
+	assert.Equal(t, "SELECT id FROM events LIMIT -1 OFFSET ?", q.String())
+
+	q = &query{base: "SELECT id FROM events"}
+	q.page(postgresDialect, 0, 5)
+	assert.Equal(t, "SELECT id FROM events LIMIT ALL OFFSET ?", q.String())
+}
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "%night%", likePattern("night"))
+	assert.Equal(t, `%100\%\_a\\b%`, likePattern(`100%_a\b`))
+}
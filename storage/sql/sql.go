@@ -4,30 +4,123 @@ import (
 	"database/sql"
 
 	"github.com/pkg/errors"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
 )
 
+// queryer is the subset of database/sql shared by *sql.DB and *sql.Tx, so
+// that every storage method can run either directly or inside a transaction.
+type queryer interface {
+	Prepare(query string) (*sql.Stmt, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 type SQLStorage struct {
-	db *sql.DB
+	db      *sql.DB
+	q       queryer
+	tx      *sql.Tx
+	dialect *dialect
 }
 
 func (s *SQLStorage) Close() {
+	if s.tx != nil {
+		// The owner of the transaction is responsible for the connection.
+		return
+	}
 	s.db.Close()
 }
 
+func newSQLStorage(db *sql.DB, d *dialect) *SQLStorage {
+	return &SQLStorage{db: db, q: d.wrap(db), dialect: d}
+}
+
+// NewSQLStorage wraps a SQLite db and migrates it forward to the latest
+// schema version.
 func NewSQLStorage(db *sql.DB) (*SQLStorage, error) {
-	storage := &SQLStorage{db: db}
+	storage := newSQLStorage(db, sqliteDialect)
 	return storage, storage.init()
 }
 
+// NewUnmigratedSQLStorage wraps a SQLite db without applying any pending
+// migrations, for callers that want to inspect or control the schema
+// version themselves.
+func NewUnmigratedSQLStorage(db *sql.DB) (*SQLStorage, error) {
+	storage := newSQLStorage(db, sqliteDialect)
+	return storage, storage.createSchemaVersionTable()
+}
+
 func (s *SQLStorage) init() error {
-	err := s.CreateAttendeesTable()
+	err := s.createSchemaVersionTable()
 	if err != nil {
-		return errors.Wrap(err, "error creating attendees table")
+		return err
 	}
-	err = s.CreateEventsTable()
+	err = s.Migrate(LatestSchemaVersion())
 	if err != nil {
-		return errors.Wrap(err, "error creating events table")
-
+		return errors.Wrap(err, "error migrating database schema")
 	}
 	return nil
 }
+
+var _ interfaces.Store = &SQLStorage{}
+
+// WithTx runs fn against a copy of the storage whose methods all execute in
+// a single transaction. The transaction is committed if fn returns nil and
+// rolled back otherwise. Calling WithTx on storage that is already scoped to
+// a transaction runs fn in that same transaction.
+func (s *SQLStorage) WithTx(fn func(tx interfaces.Store) error) error {
+	return s.withTx(func(tx *SQLStorage) error {
+		return fn(tx)
+	})
+}
+
+func (s *SQLStorage) withTx(fn func(tx *SQLStorage) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "error beginning transaction")
+	}
+	scoped := &SQLStorage{db: s.db, q: s.dialect.wrap(tx), tx: tx, dialect: s.dialect}
+	if err := fn(scoped); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.WithError(rollbackErr).Error("error rolling back transaction")
+		}
+		return err
+	}
+	return errors.Wrap(tx.Commit(), "error committing transaction")
+}
+
+// upsert runs an INSERT ... ON CONFLICT DO UPDATE statement whose update is
+// guarded by a WHERE clause that only matches when a column differs, and
+// uses existsQuery to tell an insert apart from an update.
+func (s *SQLStorage) upsert(existsQuery string, key []interface{}, upsertStatement string, args ...interface{}) (interfaces.UpsertResult, error) {
+	upsertStatement = s.dialect.upsert(upsertStatement)
+	result := interfaces.Unchanged
+	err := s.withTx(func(tx *SQLStorage) error {
+		var existing int
+		if err := tx.q.QueryRow(existsQuery, key...).Scan(&existing); err != nil {
+			return errors.Wrap(err, "error checking for existing row")
+		}
+		res, err := tx.q.Exec(upsertStatement, args...)
+		if err != nil {
+			return errors.Wrap(err, "error while executing upsert statement")
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "error reading upsert result")
+		}
+		switch {
+		case affected == 0:
+			result = interfaces.Unchanged
+		case existing == 0:
+			result = interfaces.Inserted
+		default:
+			result = interfaces.Updated
+		}
+		return nil
+	})
+	return result, err
+}
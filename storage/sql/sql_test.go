@@ -1 +1,54 @@
 package storage
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexthemitchell/community-attendance/models"
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func newTestStorage(t *testing.T) (*SQLStorage, func()) {
+	dir, err := ioutil.TempDir("", "attendance-storage")
+	assert.NoError(t, err)
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "test.db"))
+	assert.NoError(t, err)
+	storage, err := NewSQLStorage(db)
+	assert.NoError(t, err)
+	return storage, func() {
+		storage.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	storage, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	now := time.Now().UTC()
+	err := storage.WithTx(func(tx interfaces.Store) error {
+		_, err := tx.UpsertEvent(models.NewEvent("Rolled Back", "event-1", &now))
+		assert.NoError(t, err)
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+	count, err := storage.CountEvents()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), count)
+
+	err = storage.WithTx(func(tx interfaces.Store) error {
+		_, err := tx.UpsertEvent(models.NewEvent("Committed", "event-2", &now))
+		return err
+	})
+	assert.NoError(t, err)
+	count, err = storage.CountEvents()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), count)
+}
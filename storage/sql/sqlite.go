@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+
+	interfaces "github.com/alexthemitchell/community-attendance/storage/interfaces"
+)
+
+func init() {
+	interfaces.Register("sqlite", OpenSQLite)
+}
+
+// SQLitePath extracts the database file path from a DSN of the form
+// "sqlite:///absolute/path.db", "sqlite://relative/path.db" or a bare path.
+func SQLitePath(dsn string) string {
+	return strings.TrimPrefix(dsn, "sqlite://")
+}
+
+// OpenSQLite opens and migrates the SQLite database named by dsn.
+func OpenSQLite(dsn string) (interfaces.Store, error) {
+	path := SQLitePath(dsn)
+	if path == "" {
+		return nil, errors.Errorf("no database file in DSN %#v", dsn)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening DB file %#v", path)
+	}
+	s, err := NewSQLStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "error initializing SQL storage")
+	}
+	return s, nil
+}
@@ -0,0 +1,66 @@
+// Package timezone converts the wall clock times people write down into
+// instants, taking daylight saving time into account.
+package timezone
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// wallClockLayout is how wall clock times are shown in errors.
+const wallClockLayout = "January 2, 2006 3:04 PM"
+
+// Date returns the instant at which clocks in location show the date and
+// time of day of wall, ignoring wall's own location. Wall clock times that
+// a daylight saving change skips are an error. Those that happen twice,
+// when clocks are turned back, are taken to mean the first.
+func Date(wall time.Time, location *time.Location) (time.Time, error) {
+	instants := occurrences(wall, location)
+	if len(instants) == 0 {
+		return time.Time{}, errors.Errorf("%s doesn't happen in %s: clocks skip it for daylight saving time",
+			wall.Format(wallClockLayout), location)
+	}
+	return instants[0], nil
+}
+
+// Repeated reports whether clocks in location show the date and time of day
+// of wall twice, because they are turned back over it.
+func Repeated(wall time.Time, location *time.Location) bool {
+	return len(occurrences(wall, location)) > 1
+}
+
+// occurrences returns the instants, earliest first, at which clocks in
+// location show the date and time of day of wall.
+func occurrences(wall time.Time, location *time.Location) []time.Time {
+	year, month, day := wall.Date()
+	hour, minute, second := wall.Clock()
+	sameWallClock := func(t time.Time) bool {
+		t = t.In(location)
+		y, mo, d := t.Date()
+		h, mi, s := t.Clock()
+		return y == year && mo == month && d == day && h == hour && mi == minute && s == second
+	}
+
+	t := time.Date(year, month, day, hour, minute, second, wall.Nanosecond(), location)
+	if !sameWallClock(t) {
+		return nil
+	}
+	// A wall clock time that happens twice is read with the offset in effect
+	// either before or after the change; try both.
+	instants := []time.Time{t}
+	utc := time.Date(year, month, day, hour, minute, second, wall.Nanosecond(), time.UTC)
+	for _, near := range []time.Time{t.Add(-24 * time.Hour), t.Add(24 * time.Hour)} {
+		_, offset := near.In(location).Zone()
+		candidate := utc.Add(-time.Duration(offset) * time.Second).In(location)
+		if candidate.Equal(t) || !sameWallClock(candidate) {
+			continue
+		}
+		if candidate.Before(t) {
+			instants = []time.Time{candidate, t}
+		} else {
+			instants = append(instants, candidate)
+		}
+	}
+	return instants
+}
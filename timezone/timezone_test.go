@@ -0,0 +1,61 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestDate(t *testing.T) {
+	losAngeles := mustLoad(t, "America/Los_Angeles")
+	london := mustLoad(t, "Europe/London")
+	for _, test := range []struct {
+		wall     time.Time
+		location *time.Location
+		expected time.Time
+	}{
+		// Either side of the spring and fall changes in Los Angeles.
+		{time.Date(2019, time.March, 9, 18, 30, 0, 0, time.UTC), losAngeles, time.Date(2019, time.March, 10, 2, 30, 0, 0, time.UTC)},
+		{time.Date(2019, time.March, 10, 18, 30, 0, 0, time.UTC), losAngeles, time.Date(2019, time.March, 11, 1, 30, 0, 0, time.UTC)},
+		{time.Date(2019, time.March, 10, 3, 0, 0, 0, time.UTC), losAngeles, time.Date(2019, time.March, 10, 10, 0, 0, 0, time.UTC)},
+		{time.Date(2019, time.November, 2, 18, 30, 0, 0, time.UTC), losAngeles, time.Date(2019, time.November, 3, 1, 30, 0, 0, time.UTC)},
+		{time.Date(2019, time.November, 3, 18, 30, 0, 0, time.UTC), losAngeles, time.Date(2019, time.November, 4, 2, 30, 0, 0, time.UTC)},
+		// 1:30 AM happens twice on November 3; the first is still PDT.
+		{time.Date(2019, time.November, 3, 1, 30, 0, 0, time.UTC), losAngeles, time.Date(2019, time.November, 3, 8, 30, 0, 0, time.UTC)},
+		{time.Date(2019, time.October, 27, 1, 30, 0, 0, time.UTC), london, time.Date(2019, time.October, 27, 0, 30, 0, 0, time.UTC)},
+		// The wall clock's own location doesn't matter.
+		{time.Date(2019, time.July, 4, 18, 30, 0, 0, london), losAngeles, time.Date(2019, time.July, 5, 1, 30, 0, 0, time.UTC)},
+		{time.Date(2019, time.July, 4, 18, 30, 0, 0, time.UTC), time.UTC, time.Date(2019, time.July, 4, 18, 30, 0, 0, time.UTC)},
+	} {
+		actual, err := Date(test.wall, test.location)
+		assert.NoError(t, err, test.wall.String())
+		assert.Equal(t, test.expected, actual.UTC(), test.wall.String())
+		assert.Equal(t, test.location, actual.Location())
+	}
+}
+
+func TestDateSkippedByDaylightSaving(t *testing.T) {
+	_, err := Date(time.Date(2019, time.March, 10, 2, 30, 0, 0, time.UTC), mustLoad(t, "America/Los_Angeles"))
+	assert.EqualError(t, err, "March 10, 2019 2:30 AM doesn't happen in America/Los_Angeles: clocks skip it for daylight saving time")
+	_, err = Date(time.Date(2019, time.March, 31, 1, 15, 0, 0, time.UTC), mustLoad(t, "Europe/London"))
+	assert.Error(t, err)
+}
+
+func TestRepeated(t *testing.T) {
+	losAngeles := mustLoad(t, "America/Los_Angeles")
+	assert.True(t, Repeated(time.Date(2019, time.November, 3, 1, 30, 0, 0, time.UTC), losAngeles))
+	assert.False(t, Repeated(time.Date(2019, time.November, 3, 2, 30, 0, 0, time.UTC), losAngeles))
+	assert.False(t, Repeated(time.Date(2019, time.November, 3, 0, 30, 0, 0, time.UTC), losAngeles))
+	assert.False(t, Repeated(time.Date(2019, time.March, 10, 2, 30, 0, 0, time.UTC), losAngeles))
+	assert.True(t, Repeated(time.Date(2019, time.October, 27, 1, 0, 0, 0, time.UTC), mustLoad(t, "Europe/London")))
+	assert.False(t, Repeated(time.Date(2019, time.October, 27, 1, 0, 0, 0, time.UTC), time.UTC))
+}